@@ -75,8 +75,8 @@ for SHARD in "non_han" "han_1char" "han_2char" "han_3plus"; do
         INDEX_COUNT=$(find "$SHARD_DIR/index" -type f -name "*.json.br" | wc -l)
         JMDICT_COUNT=$(find "$SHARD_DIR/j" -type f -name "*.json.br" | wc -l)
         JMNEDICT_COUNT=$(find "$SHARD_DIR/n" -type f -name "*.json.br" | wc -l)
-        KANJIDIC_COUNT=$(find "$SHARD_DIR/d" -type f -name "*.json.br" | wc -l)
-        CHINESE_CHARS_COUNT=$(find "$SHARD_DIR/c" -type f -name "*.json.br" | wc -l)
+        KANJIDIC_COUNT=$(find "$SHARD_DIR/d" -type f -name "*.json.br" ! -name "*.s.json.br" ! -name "*.g.json.br" | wc -l)
+        CHINESE_CHARS_COUNT=$(find "$SHARD_DIR/c" -type f -name "*.json.br" ! -name "*.s.json.br" ! -name "*.g.json.br" | wc -l)
         CHINESE_WORDS_COUNT=$(find "$SHARD_DIR/w" -type f -name "*.json.br" | wc -l)
         TOTAL_COUNT=$((JMDICT_COUNT + JMNEDICT_COUNT + KANJIDIC_COUNT + CHINESE_CHARS_COUNT + CHINESE_WORDS_COUNT))
 
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+
+	"kiokun-go/processor"
 )
 
 // OutputMode determines which words to output
@@ -20,6 +22,12 @@ const (
 	OutputNonHanOnly OutputMode = "non-han"   // Output words with at least one non-Han character
 )
 
+// Chinese word sources selectable with -chinese-words-source
+const (
+	ChineseWordsDongChinese = "dong-chinese" // The dong-chinese word dump
+	ChineseWordsCEDICT      = "cedict"       // CC-CEDICT
+)
+
 // Config holds all configuration options for the application
 type Config struct {
 	DictDir       string
@@ -43,6 +51,18 @@ type Config struct {
 	OnlyChineseChars bool
 	OnlyChineseWords bool
 	OnlyIDS          bool
+
+	// ChineseWordSource selects the dictionary Chinese words are read from
+	ChineseWordSource string
+
+	// N-gram contained-in index settings (MaxLength is 0 when the index is disabled)
+	Ngrams processor.NgramConfig
+
+	// ConsolidateKeys writes every index key to its own shard instead of each entry's shard
+	ConsolidateKeys bool
+
+	// CompactPostings writes posting lists as base64 varint deltas instead of JSON arrays
+	CompactPostings bool
 }
 
 // LogFunc is a function that logs messages based on silent mode
@@ -80,6 +100,18 @@ func ParseConfig() (*Config, LogFunc, error) {
 	onlyChineseChars := flag.Bool("only-chinese-chars", false, "Process only Chinese characters")
 	onlyChineseWords := flag.Bool("only-chinese-words", false, "Process only Chinese words")
 	onlyIDS := flag.Bool("only-ids", false, "Process only IDS (Ideographic Description Sequences)")
+	chineseWordSource := flag.String("chinese-words-source", ChineseWordsDongChinese, "Chinese word source: 'dong-chinese' or 'cedict'")
+
+	compactPostings := flag.Bool("compact-postings", false, "Write index posting lists as base64 varint deltas instead of JSON arrays")
+	consolidateKeys := flag.Bool("consolidate-keys", false, "Write every index key to the shard of the key itself, so clients read one index file per lookup")
+
+	// N-gram index flags
+	defaultNgrams := processor.DefaultNgramConfig()
+	ngrams := flag.Bool("ngrams", false, "Build the n-gram contained-in index (words containing multi-character substrings)")
+	ngramMin := flag.Int("ngram-min", defaultNgrams.MinLength, "Shortest substring in the n-gram index")
+	ngramMax := flag.Int("ngram-max", defaultNgrams.MaxLength, "Longest substring in the n-gram index")
+	ngramKana := flag.Bool("ngram-kana", defaultNgrams.IncludeKana, "Also index substrings containing kana in the n-gram index")
+	ngramLimit := flag.Int("ngram-limit", defaultNgrams.MaxPostings, "Most entries kept per n-gram key and dictionary (0 = no limit)")
 	flag.Parse()
 
 	// Create logging function
@@ -96,6 +128,25 @@ func ParseConfig() (*Config, LogFunc, error) {
 		return nil, logf, fmt.Errorf("invalid output mode: %s", *outputModeFlag)
 	}
 
+	// Validate the Chinese word source
+	if *chineseWordSource != ChineseWordsDongChinese && *chineseWordSource != ChineseWordsCEDICT {
+		return nil, logf, fmt.Errorf("invalid Chinese word source: %s", *chineseWordSource)
+	}
+
+	// Validate the n-gram index settings
+	var ngramConfig processor.NgramConfig
+	if *ngrams {
+		ngramConfig = processor.NgramConfig{
+			MinLength:   *ngramMin,
+			MaxLength:   *ngramMax,
+			IncludeKana: *ngramKana,
+			MaxPostings: *ngramLimit,
+		}
+		if err := ngramConfig.Validate(); err != nil {
+			return nil, logf, err
+		}
+	}
+
 	// Modify output directory based on mode
 	if outputMode == OutputHanOnly {
 		*outputDir = *outputDir + "_han"
@@ -168,5 +219,11 @@ func ParseConfig() (*Config, LogFunc, error) {
 		OnlyChineseChars: *onlyChineseChars,
 		OnlyChineseWords: *onlyChineseWords,
 		OnlyIDS:          *onlyIDS,
+
+		ChineseWordSource: *chineseWordSource,
+
+		Ngrams:          ngramConfig,
+		ConsolidateKeys: *consolidateKeys,
+		CompactPostings: *compactPostings,
 	}, logf, nil
 }
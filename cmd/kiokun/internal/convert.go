@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/hanconv"
+)
+
+// RunConvert implements the "convert" subcommand: it converts Chinese text between
+// traditional and simplified script using the imported Chinese dictionaries. Text
+// comes from the arguments or, if none are given, from standard input line by line.
+func RunConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	dictDir := flags.String("dictdir", "dictionaries", "Base directory containing dictionary packages")
+	to := flags.String("to", "traditional", "Target script: 'traditional' or 'simplified'")
+	report := flags.Bool("ambiguities", false, "Report characters with more than one conversion on standard error")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var dir hanconv.Direction
+	switch *to {
+	case "traditional", "t":
+		dir = hanconv.ToTraditional
+	case "simplified", "s":
+		dir = hanconv.ToSimplified
+	default:
+		return fmt.Errorf("invalid target script: %s", *to)
+	}
+
+	// Import only the Chinese dictionaries
+	common.SetDictionariesBasePath(*dictDir)
+	var entries []common.Entry
+	for _, dict := range common.GetRegisteredDictionaries() {
+		if dict.Name != "chinese_words" && dict.Name != "chinese_chars" {
+			continue
+		}
+		imported, err := dict.Importer.Import(filepath.Join(dict.SourceDir, dict.InputFile))
+		if err != nil {
+			return fmt.Errorf("error importing %s: %v", dict.Name, err)
+		}
+		entries = append(entries, imported...)
+	}
+	converter := hanconv.NewConverter(entries)
+
+	convert := func(text string, w io.Writer) {
+		converted, ambiguities := converter.Convert(text, dir)
+		fmt.Fprintln(w, converted)
+		if *report {
+			for _, a := range ambiguities {
+				fmt.Fprintf(os.Stderr, "%d: %s → %s (%s)\n", a.Offset, a.Source, a.Chosen, strings.Join(a.Options, "/"))
+			}
+		}
+	}
+
+	if flags.NArg() > 0 {
+		convert(strings.Join(flags.Args(), " "), os.Stdout)
+		return nil
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		convert(scanner.Text(), os.Stdout)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading input: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"kiokun-go/difficulty"
+	"kiokun-go/lookup"
+)
+
+// RunDifficulty implements the "difficulty" subcommand: it reports the vocabulary
+// and character difficulty profile of a Japanese or Chinese text file
+func RunDifficulty(args []string) error {
+	flags := flag.NewFlagSet("difficulty", flag.ContinueOnError)
+	dictDir := flags.String("dict", "output", "Base directory of the built dictionary (as passed to -outdir)")
+	asJSON := flags.Bool("json", false, "Write the report as JSON, including the entries of hard items")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: kiokun difficulty [-dict dir] [-json] <text file>")
+	}
+
+	text, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("error reading text file: %v", err)
+	}
+
+	dict, err := lookup.Open(*dictDir)
+	if err != nil {
+		return err
+	}
+	report, err := difficulty.Analyze(dict, string(text))
+	if err != nil {
+		return fmt.Errorf("error analyzing text: %v", err)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(report)
+	}
+	report.WriteText(os.Stdout)
+	return nil
+}
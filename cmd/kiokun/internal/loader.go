@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/ids"
 )
 
 // DictionaryEntries holds entries from all dictionaries
@@ -16,6 +17,9 @@ type DictionaryEntries struct {
 	ChineseChars []common.Entry
 	ChineseWords []common.Entry
 	IDS          []common.Entry
+	Kradfile     []common.Entry
+	KanjiVG      []common.Entry
+	MakeMeAHanzi []common.Entry
 }
 
 // LoadDictionaries loads all dictionaries and returns their entries
@@ -32,13 +36,20 @@ func LoadDictionaries(config *Config, logf LogFunc) (*DictionaryEntries, error)
 	dictConfigs := common.GetRegisteredDictionaries()
 
 	// Import each dictionary
-	var jmdictEntries, jmnedictEntries, kanjidicEntries, chineseCharsEntries, chineseWordsEntries, idsEntries []common.Entry
+	var jmdictEntries, jmnedictEntries, kanjidicEntries, chineseCharsEntries, chineseWordsEntries, idsEntries, kradfileEntries, kanjivgEntries, hanziEntries []common.Entry
 
 	// Check if any specific dictionary is selected
 	onlySpecificDict := config.OnlyJMdict || config.OnlyJMNedict || config.OnlyKanjidic ||
 		config.OnlyChineseChars || config.OnlyChineseWords || config.OnlyIDS
 
 	for _, dict := range dictConfigs {
+		// Import Chinese words from the selected source only
+		if (dict.Name == "chinese_words" && config.ChineseWordSource == ChineseWordsCEDICT) ||
+			(dict.Name == "cedict" && config.ChineseWordSource != ChineseWordsCEDICT) {
+			logf("Skipping %s (Chinese words come from %s)\n", dict.Name, config.ChineseWordSource)
+			continue
+		}
+
 		// Skip dictionaries that are not selected when using specific dictionary flags
 		if onlySpecificDict {
 			switch dict.Name {
@@ -62,12 +73,33 @@ func LoadDictionaries(config *Config, logf LogFunc) (*DictionaryEntries, error)
 					logf("Skipping %s (not selected)\n", dict.Name)
 					continue
 				}
-			case "chinese_words":
+			case "chinese_words", "cedict":
 				if !config.OnlyChineseWords {
 					logf("Skipping %s (not selected)\n", dict.Name)
 					continue
 				}
-			case "ids", "ids_ext_a":
+			case "kradfile":
+				// KRADFILE radicals are only used for Kanjidic entries
+				if !config.OnlyKanjidic {
+					logf("Skipping %s (not selected)\n", dict.Name)
+					continue
+				}
+			case "kanjivg":
+				// KanjiVG strokes are only written for Kanjidic and Chinese character entries
+				if !config.OnlyKanjidic && !config.OnlyChineseChars {
+					logf("Skipping %s (not selected)\n", dict.Name)
+					continue
+				}
+			case "makemeahanzi":
+				// Make Me a Hanzi data is only used for Chinese character entries
+				if !config.OnlyChineseChars {
+					logf("Skipping %s (not selected)\n", dict.Name)
+					continue
+				}
+			default:
+				if !ids.IsIDSDictionary(dict.Name) {
+					break
+				}
 				// Always load IDS dictionaries for character composition data
 				// but only if we're processing Kanjidic or Chinese Chars
 				if !config.OnlyIDS && !config.OnlyKanjidic && !config.OnlyChineseChars {
@@ -99,11 +131,19 @@ func LoadDictionaries(config *Config, logf LogFunc) (*DictionaryEntries, error)
 			kanjidicEntries = entries
 		case "chinese_chars":
 			chineseCharsEntries = entries
-		case "chinese_words":
+		case "chinese_words", "cedict":
 			chineseWordsEntries = entries
-		case "ids", "ids_ext_a":
-			// Append IDS entries from different files
-			idsEntries = append(idsEntries, entries...)
+		case "kradfile":
+			kradfileEntries = entries
+		case "kanjivg":
+			kanjivgEntries = entries
+		case "makemeahanzi":
+			hanziEntries = entries
+		default:
+			if ids.IsIDSDictionary(dict.Name) {
+				// Append IDS entries from different files
+				idsEntries = append(idsEntries, entries...)
+			}
 		}
 
 		logf("Imported %s: %d entries (%.2fs)\n", dict.Name, len(entries), time.Since(startTime).Seconds())
@@ -116,5 +156,8 @@ func LoadDictionaries(config *Config, logf LogFunc) (*DictionaryEntries, error)
 		ChineseChars: chineseCharsEntries,
 		ChineseWords: chineseWordsEntries,
 		IDS:          idsEntries,
+		Kradfile:     kradfileEntries,
+		KanjiVG:      kanjivgEntries,
+		MakeMeAHanzi: hanziEntries,
 	}, nil
 }
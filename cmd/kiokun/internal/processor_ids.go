@@ -4,12 +4,34 @@ import (
 	"fmt"
 	"time"
 
+	"kiokun-go/cognates"
+	"kiokun-go/components"
 	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/ids"
+	"kiokun-go/dictionaries/kanjivg"
+	"kiokun-go/dictionaries/kradfile"
+	"kiokun-go/dictionaries/makemeahanzi"
+	"kiokun-go/furigana"
 	"kiokun-go/processor"
+	"kiokun-go/variants"
 )
 
-// ProcessEntriesWithIDS processes dictionary entries with IDS data and writes them to files
-func ProcessEntriesWithIDS(entries *DictionaryEntries, config *Config, logf LogFunc, idsMap map[string]string) error {
+// Resources holds the lookup tables built from the support dictionaries, which the
+// processor uses to enrich entries and write links and companion files
+type Resources struct {
+	IDS           map[string]ids.IDSEntry
+	KanjiReadings furigana.Readings
+	Variants      *variants.Graph
+	Cognates      []cognates.Pair
+	Components    *components.Index
+	Radicals      *kradfile.Table
+	Strokes       map[string]kanjivg.Character
+	Hanzi         map[string]makemeahanzi.Character
+}
+
+// ProcessEntriesWithIDS processes dictionary entries with IDS data and the other
+// resources and writes them to files
+func ProcessEntriesWithIDS(entries *DictionaryEntries, config *Config, logf LogFunc, res Resources) error {
 	// Always use the sharded index-based processor
 	logf("Using sharded index-based processor with separate files for each dictionary and shard\n")
 	proc, err := processor.NewShardedIndexProcessor(config.OutputDir, config.FileWriters)
@@ -19,7 +41,47 @@ func ProcessEntriesWithIDS(entries *DictionaryEntries, config *Config, logf LogF
 	}
 
 	// Set the IDS map in the processor
-	proc.SetIDSMap(idsMap)
+	proc.SetIDSMap(res.IDS)
+
+	// Set the kanji readings used for furigana alignment
+	proc.SetKanjiReadings(res.KanjiReadings)
+
+	// Set the character variants used for alias links
+	proc.SetVariants(res.Variants)
+
+	// Set the Japanese/Chinese cognate pairs linked from both entries
+	proc.SetCognates(res.Cognates)
+
+	// Set the character components used for component links
+	proc.SetComponents(res.Components)
+
+	// Set the KRADFILE radicals added to Kanjidic entries and the radical picker
+	proc.SetRadicals(res.Radicals)
+
+	// Set the KanjiVG stroke data written next to single character entries
+	proc.SetStrokes(res.Strokes)
+
+	// Set the Make Me a Hanzi data added to Chinese character entries
+	proc.SetHanzi(res.Hanzi)
+
+	// Keep every key in its own shard if requested
+	if config.ConsolidateKeys {
+		logf("Consolidating index keys into their own shards\n")
+		proc.SetConsolidateKeys(true)
+	}
+
+	// Encode posting lists compactly if requested
+	if config.CompactPostings {
+		logf("Writing compact posting lists\n")
+		proc.SetCompactPostings(true)
+	}
+
+	// Enable the n-gram contained-in index if requested
+	if config.Ngrams.MaxLength > 0 {
+		logf("Building n-gram index (%d-%d characters, kana: %v, limit: %d)\n",
+			config.Ngrams.MinLength, config.Ngrams.MaxLength, config.Ngrams.IncludeKana, config.Ngrams.MaxPostings)
+		proc.SetNgrams(config.Ngrams)
+	}
 
 	// Calculate total entries and pre-allocate the slice
 	totalEntries := len(entries.JMdict) + len(entries.JMNedict) + len(entries.Kanjidic) +
@@ -38,7 +100,7 @@ func ProcessEntriesWithIDS(entries *DictionaryEntries, config *Config, logf LogF
 	// Log entry counts directly from the source slices to avoid type assertions
 	logf("Processing %d entries (%d JMdict, %d JMNedict, %d Kanjidic, %d Chinese Chars, %d Chinese Words) with %d IDS entries\n",
 		totalEntries, len(entries.JMdict), len(entries.JMNedict), len(entries.Kanjidic),
-		len(entries.ChineseChars), len(entries.ChineseWords), len(idsMap))
+		len(entries.ChineseChars), len(entries.ChineseWords), len(res.IDS))
 
 	// Process entries in batches with progress reporting
 	logf("Processing entries in batches...\n")
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"kiokun-go/lookup"
+	"kiokun-go/ruby"
+)
+
+// RunRuby implements the "ruby" subcommand: it annotates Japanese text with furigana
+// read from a built dictionary. Text comes from the arguments or, if none are given,
+// from standard input line by line.
+func RunRuby(args []string) error {
+	flags := flag.NewFlagSet("ruby", flag.ContinueOnError)
+	dictDir := flags.String("dict", "output", "Base directory of the built dictionary (as passed to -outdir)")
+	format := flags.String("format", "html", "Output format: 'html', 'brackets' or 'json'")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	dict, err := lookup.Open(*dictDir)
+	if err != nil {
+		return err
+	}
+	annotator := ruby.NewAnnotator(dict)
+
+	annotate := func(text string, w io.Writer) error {
+		spans, err := annotator.Annotate(text)
+		if err != nil {
+			return fmt.Errorf("error annotating text: %v", err)
+		}
+		out, err := ruby.Render(spans, ruby.Format(*format))
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, out)
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		return annotate(strings.Join(flags.Args(), " "), os.Stdout)
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if err := annotate(scanner.Text(), os.Stdout); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading input: %v", err)
+	}
+	return nil
+}
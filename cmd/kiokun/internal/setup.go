@@ -65,6 +65,13 @@ func SetupDictionaryFiles(logf LogFunc) error {
 			IsJSONL:     true,
 			JSONLTarget: "dictionaries/chinese_words/source/dictionary_word_2024-06-17.json",
 		},
+		// CC-CEDICT, read gzipped; used with -chinese-words-source=cedict
+		{
+			Name:       "CC-CEDICT",
+			URL:        "https://www.mdbg.net/chinese/export/cedict/cedict_1_0_ts_utf-8_mdbg.txt.gz",
+			TargetPath: "dictionaries/cedict/source/cedict_1_0_ts_utf-8_mdbg.txt.gz",
+			IsZip:      false,
+		},
 		// IDS
 		{
 			Name:       "IDS",
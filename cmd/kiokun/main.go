@@ -4,20 +4,49 @@ import (
 	"fmt"
 	"os"
 
+	"kiokun-go/cognates"
+	"kiokun-go/components"
 	// Import for side effects (dictionary registration)
+	_ "kiokun-go/dictionaries/cedict"
 	_ "kiokun-go/dictionaries/chinese_chars"
 	_ "kiokun-go/dictionaries/chinese_words"
+	"kiokun-go/dictionaries/common"
 	"kiokun-go/dictionaries/ids"
 	_ "kiokun-go/dictionaries/ids"
 	_ "kiokun-go/dictionaries/jmdict"
 	_ "kiokun-go/dictionaries/jmnedict"
 	_ "kiokun-go/dictionaries/kanjidic"
+	"kiokun-go/dictionaries/kanjivg"
+	"kiokun-go/dictionaries/kradfile"
+	"kiokun-go/dictionaries/makemeahanzi"
+	"kiokun-go/furigana"
+	"kiokun-go/variants"
 
 	// Import local package functions
 	. "kiokun-go/cmd/kiokun/internal"
 )
 
 func main() {
+	// Subcommands that read an already built dictionary
+	if len(os.Args) > 1 {
+		var run func([]string) error
+		switch os.Args[1] {
+		case "ruby":
+			run = RunRuby
+		case "difficulty":
+			run = RunDifficulty
+		case "convert":
+			run = RunConvert
+		}
+		if run != nil {
+			if err := run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	// Parse configuration
 	config, logf, err := ParseConfig()
 	if err != nil {
@@ -46,21 +75,73 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create IDS lookup map
-	idsMap := make(map[string]string)
+	// Create IDS lookup map, keeping every regional variant so Kanjidic and Chinese
+	// character entries can each use their own region's decomposition
+	idsMap := make(map[string]ids.IDSEntry)
 	for _, entry := range entries.IDS {
 		if idsEntry, ok := entry.(ids.IDSEntry); ok {
-			idsMap[idsEntry.Character] = idsEntry.IDS
+			idsMap[idsEntry.Character] = idsEntry
 		}
 	}
 
 	logf("Created IDS lookup map with %d entries\n", len(idsMap))
 
+	// Create kanji reading table for furigana alignment (before filtering, so
+	// words keep their readings even when Kanjidic entries are filtered out)
+	kanjiReadings := furigana.ReadingsFromKanjidic(entries.Kanjidic)
+	logf("Created kanji reading table with %d entries\n", len(kanjiReadings))
+
+	// Create character variant graph (shinjitai/kyūjitai, simplified/traditional, Kanjidic variants)
+	var variantSources []common.Entry
+	variantSources = append(variantSources, entries.Kanjidic...)
+	variantSources = append(variantSources, entries.ChineseChars...)
+	variantSources = append(variantSources, entries.ChineseWords...)
+	variantGraph := variants.FromEntries(variantSources)
+	logf("Created variant graph with %d characters\n", len(variantGraph.Characters()))
+
+	// Create component index from IDS decompositions, ranked by Kanjidic and Chinese character data
+	var componentSources []common.Entry
+	componentSources = append(componentSources, entries.IDS...)
+	componentSources = append(componentSources, entries.Kanjidic...)
+	componentSources = append(componentSources, entries.ChineseChars...)
+	componentIndex := components.FromEntries(componentSources)
+	logf("Created component index with %d components\n", len(componentIndex.All()))
+
+	// Create KRADFILE radical table, ranked by Kanjidic stroke counts and frequencies
+	var radicalSources []common.Entry
+	radicalSources = append(radicalSources, entries.Kradfile...)
+	radicalSources = append(radicalSources, entries.Kanjidic...)
+	radicalTable := kradfile.FromEntries(radicalSources)
+	logf("Created radical table with %d radicals\n", len(radicalTable.Radicals()))
+
+	// Create KanjiVG stroke lookup map
+	strokes := kanjivg.ByCharacter(entries.KanjiVG)
+	logf("Created stroke order map with %d characters\n", len(strokes))
+
+	// Create Make Me a Hanzi lookup map
+	hanzi := makemeahanzi.ByCharacter(entries.MakeMeAHanzi)
+	logf("Created Make Me a Hanzi map with %d characters\n", len(hanzi))
+
 	// Filter entries
 	filteredEntries := FilterEntries(entries, config, logf)
 
-	// Process entries with IDS map
-	if err := ProcessEntriesWithIDS(filteredEntries, config, logf, idsMap); err != nil {
+	// Pair Japanese and Chinese words written the same way (after filtering, so
+	// links only point to entries that are written)
+	cognatePairs := cognates.Find(filteredEntries.JMdict, filteredEntries.ChineseWords, variantGraph)
+	logf("Found %d Japanese/Chinese cognate pairs\n", len(cognatePairs))
+
+	// Process entries with IDS map, kanji readings, variants, cognates, components, radicals, strokes and Make Me a Hanzi data
+	resources := Resources{
+		IDS:           idsMap,
+		KanjiReadings: kanjiReadings,
+		Variants:      variantGraph,
+		Cognates:      cognatePairs,
+		Components:    componentIndex,
+		Radicals:      radicalTable,
+		Strokes:       strokes,
+		Hanzi:         hanzi,
+	}
+	if err := ProcessEntriesWithIDS(filteredEntries, config, logf, resources); err != nil {
 		fmt.Fprintf(os.Stderr, "Error processing entries: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,165 @@
+package cognates
+
+import (
+	"strings"
+	"unicode"
+
+	"kiokun-go/dictionaries/chinese_words"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/jmdict"
+	"kiokun-go/variants"
+)
+
+// minOverlap is the share of the smaller gloss vocabulary that must also appear
+// in the other entry's glosses for the meanings to count as overlapping
+const minOverlap = 0.25
+
+// Pair is a JMdict word and a Chinese word sharing a written form
+type Pair struct {
+	Word    jmdict.Word
+	Chinese chinese_words.ChineseWordEntry
+	Form    string // Kanji form of the JMdict word that matched
+	Text    string // Traditional or simplified form of the Chinese word that matched
+	Overlap bool   // Whether the English glosses of the two words overlap
+}
+
+// Find pairs JMdict words with Chinese words written the same way. A kanji form made
+// only of Han characters matches a Chinese traditional or simplified form when the two
+// are equal after replacing every character by its canonical variant, so 電話 matches
+// 電話/电话 and 鉄道 matches 鐵道/铁道. Each word and Chinese entry are paired at most
+// once, through the first kanji form that matches. Other entries are ignored.
+func Find(words, chinese []common.Entry, graph *variants.Graph) []Pair {
+	type form struct {
+		entry chinese_words.ChineseWordEntry
+		text  string
+	}
+
+	byForm := make(map[string][]form)
+	for _, entry := range chinese {
+		e, ok := entry.(chinese_words.ChineseWordEntry)
+		if !ok {
+			continue
+		}
+		for _, text := range []string{e.Traditional, e.Simplified} {
+			if !isHan(text) {
+				continue
+			}
+			key := graph.Normalize(text)
+			duplicate := false
+			for _, f := range byForm[key] {
+				if f.entry.ID == e.ID {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				byForm[key] = append(byForm[key], form{entry: e, text: text})
+			}
+		}
+	}
+
+	var pairs []Pair
+	for _, entry := range words {
+		w, ok := entry.(jmdict.Word)
+		if !ok {
+			continue
+		}
+
+		paired := make(map[string]bool)
+		for _, k := range w.Kanji {
+			if !isHan(k.Text) {
+				continue
+			}
+			for _, f := range byForm[graph.Normalize(k.Text)] {
+				if paired[f.entry.ID] {
+					continue
+				}
+				paired[f.entry.ID] = true
+				pairs = append(pairs, Pair{
+					Word:    w,
+					Chinese: f.entry,
+					Form:    k.Text,
+					Text:    f.text,
+					Overlap: Overlap(Glosses(w), f.entry.Definitions),
+				})
+			}
+		}
+	}
+	return pairs
+}
+
+// Glosses returns the English glosses of every sense of a word
+func Glosses(w jmdict.Word) []string {
+	var glosses []string
+	for _, sense := range w.Sense {
+		for _, g := range sense.Gloss {
+			if g.Lang == "" || g.Lang == "eng" {
+				glosses = append(glosses, g.Text)
+			}
+		}
+	}
+	return glosses
+}
+
+// Overlap reports whether two lists of English glosses share enough content words:
+// at least a quarter of the words of the shorter list must appear in the other.
+// Function words are ignored and plurals count as their singular.
+func Overlap(a, b []string) bool {
+	wordsA, wordsB := contentWords(a), contentWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return false
+	}
+
+	shared := 0
+	for w := range wordsA {
+		if wordsB[w] {
+			shared++
+		}
+	}
+	return float64(shared)/float64(min(len(wordsA), len(wordsB))) >= minOverlap
+}
+
+// contentWords returns the distinct lowercased content words of a list of glosses
+func contentWords(glosses []string) map[string]bool {
+	words := make(map[string]bool)
+	for _, gloss := range glosses {
+		fields := strings.FieldsFunc(strings.ToLower(gloss), func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		for _, f := range fields {
+			if len(f) < 2 || stopWords[f] {
+				continue
+			}
+			if len(f) > 3 && strings.HasSuffix(f, "s") && !strings.HasSuffix(f, "ss") {
+				f = strings.TrimSuffix(f, "s")
+			}
+			words[f] = true
+		}
+	}
+	return words
+}
+
+// isHan reports whether text is non-empty and made only of Han characters
+func isHan(text string) bool {
+	if text == "" {
+		return false
+	}
+	for _, r := range text {
+		if !unicode.Is(unicode.Han, r) && r != '々' {
+			return false
+		}
+	}
+	return true
+}
+
+// stopWords are function words and dictionary boilerplate that say nothing about meaning
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "to": true, "of": true, "in": true, "on": true,
+	"at": true, "by": true, "for": true, "with": true, "from": true, "as": true, "or": true,
+	"and": true, "be": true, "is": true, "it": true, "one": true, "someone": true,
+	"something": true, "sth": true, "sb": true, "etc": true, "esp": true, "also": true,
+	"used": true, "see": true, "variant": true, "abbr": true, "cl": true, "lit": true,
+	"fig": true, "kind": true, "sort": true, "type": true, "very": true, "into": true,
+	"up": true, "out": true, "not": true, "no": true, "his": true, "her": true, "its": true,
+	"their": true, "oneself": true,
+}
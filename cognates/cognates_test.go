@@ -0,0 +1,87 @@
+package cognates
+
+import (
+	"testing"
+
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/chinese_words"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/jmdict"
+	"kiokun-go/variants"
+)
+
+func word(id, kanji string, glosses ...string) jmdict.Word {
+	var gloss []jmdict.Gloss
+	for _, g := range glosses {
+		gloss = append(gloss, jmdict.Gloss{Lang: "eng", Text: g})
+	}
+	return jmdict.Word{
+		ID:    id,
+		Kanji: []jmdict.KanjiEntry{{Text: kanji}},
+		Kana:  []jmdict.KanaEntry{{Text: "かな"}},
+		Sense: []jmdict.Sense{{Gloss: gloss}},
+	}
+}
+
+func TestFind(t *testing.T) {
+	words := []common.Entry{
+		word("1", "電話", "telephone call", "phone"),
+		word("2", "鉄道", "railroad", "railway"),
+		word("3", "手紙", "letter"),
+		word("4", "食べる", "to eat"),
+	}
+	chinese := []common.Entry{
+		chinese_words.ChineseWordEntry{ID: "10", Traditional: "電話", Simplified: "电话", Definitions: []string{"telephone", "phone call"}},
+		chinese_words.ChineseWordEntry{ID: "11", Traditional: "鐵道", Simplified: "铁道", Definitions: []string{"railway"}},
+		chinese_words.ChineseWordEntry{ID: "12", Traditional: "手紙", Simplified: "手纸", Definitions: []string{"toilet paper"}},
+	}
+	graph := variants.FromEntries([]common.Entry{
+		chinese_chars.ChineseCharEntry{ID: "1", Traditional: "鐵", Simplified: "铁"},
+	})
+
+	pairs := Find(words, chinese, graph)
+
+	expected := map[string]struct {
+		chinese string
+		overlap bool
+	}{
+		"1": {"10", true},
+		"2": {"11", true},
+		"3": {"12", false},
+	}
+	if len(pairs) != len(expected) {
+		t.Fatalf("Expected %d pairs, got %d: %+v", len(expected), len(pairs), pairs)
+	}
+	for _, p := range pairs {
+		want, ok := expected[p.Word.ID]
+		if !ok {
+			t.Errorf("Unexpected pair for word %s", p.Word.ID)
+			continue
+		}
+		if p.Chinese.ID != want.chinese {
+			t.Errorf("Word %s: expected Chinese entry %s, got %s", p.Word.ID, want.chinese, p.Chinese.ID)
+		}
+		if p.Overlap != want.overlap {
+			t.Errorf("Word %s: expected overlap %v, got %v", p.Word.ID, want.overlap, p.Overlap)
+		}
+	}
+}
+
+func TestOverlap(t *testing.T) {
+	testCases := []struct {
+		a, b     []string
+		expected bool
+	}{
+		{[]string{"railroad", "railway"}, []string{"railways"}, true},
+		{[]string{"letter"}, []string{"toilet paper"}, false},
+		{[]string{"to study"}, []string{"to study", "to learn"}, true},
+		{[]string{"to be"}, []string{"to be"}, false},
+		{nil, []string{"railway"}, false},
+	}
+
+	for _, tc := range testCases {
+		if got := Overlap(tc.a, tc.b); got != tc.expected {
+			t.Errorf("Overlap(%q, %q) = %v, expected %v", tc.a, tc.b, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package components
+
+import (
+	"sort"
+
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/ids"
+	"kiokun-go/dictionaries/kanjidic"
+)
+
+// Match is a character found by a component search
+type Match struct {
+	Character string `json:"character"`
+	Strokes   int    `json:"strokes,omitempty"`   // Stroke count, 0 when unknown
+	Frequency int    `json:"frequency,omitempty"` // Kanjidic frequency rank, 0 when unranked
+}
+
+// Index maps characters to the components they are built from and back, using
+// Ideographic Description Sequences
+type Index struct {
+	trees      map[string]*ids.Node // Parsed IDS of each character
+	strokes    map[string]int       // Stroke count of each character
+	frequency  map[string]int       // Kanjidic frequency rank of each character
+	components map[string][]string  // Every component of each character, memoized
+	characters map[string][]string  // Characters containing each component, ranked
+}
+
+// FromEntries builds an index from IDS entries, with stroke counts and frequencies
+// from Kanjidic and Chinese character entries. Characters in Kanjidic use their
+// Japanese decomposition and other Chinese characters their mainland or Taiwan one,
+// as the processor does. The IDS fields of Kanjidic and Chinese character entries
+// are used for characters without an IDS entry. Sequences that cannot be parsed are
+// skipped. Other entries are ignored.
+func FromEntries(entries []common.Entry) *Index {
+	idx := &Index{
+		trees:      make(map[string]*ids.Node),
+		strokes:    make(map[string]int),
+		frequency:  make(map[string]int),
+		components: make(map[string][]string),
+		characters: make(map[string][]string),
+	}
+
+	// Find the region of each character before picking its decomposition
+	japanese := make(map[string]bool)
+	chinese := make(map[string]bool)
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case kanjidic.Kanji:
+			japanese[e.Character] = true
+		case chinese_chars.ChineseCharEntry:
+			chinese[e.Traditional] = true
+		}
+	}
+
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case ids.IDSEntry:
+			var v ids.Variant
+			switch {
+			case japanese[e.Character]:
+				v = e.VariantForRegion("J")
+			case chinese[e.Character]:
+				v = e.VariantForRegion("G", "T")
+			default:
+				v = e.VariantForRegion()
+			}
+			if v.Tree != nil {
+				idx.trees[e.Character] = v.Tree
+			} else if tree, err := ids.Parse(v.IDS); err == nil {
+				idx.trees[e.Character] = tree
+			}
+		case kanjidic.Kanji:
+			idx.strokes[e.Character] = e.Stroke
+			idx.frequency[e.Character] = e.Frequency
+		case chinese_chars.ChineseCharEntry:
+			if _, ok := idx.strokes[e.Traditional]; !ok && e.StrokeCount > 0 {
+				idx.strokes[e.Traditional] = e.StrokeCount
+			}
+		}
+	}
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case kanjidic.Kanji:
+			idx.addIDS(e.Character, e.IDS)
+		case chinese_chars.ChineseCharEntry:
+			idx.addIDS(e.Traditional, e.IDS)
+		}
+	}
+
+	for char := range idx.trees {
+		for _, c := range idx.Components(char) {
+			idx.characters[c] = append(idx.characters[c], char)
+		}
+	}
+	for _, chars := range idx.characters {
+		Rank(chars, idx.strokes, idx.frequency)
+	}
+	return idx
+}
+
+// addIDS records a character's IDS unless it already has one
+func (idx *Index) addIDS(char, sequence string) {
+	if _, ok := idx.trees[char]; ok || sequence == "" {
+		return
+	}
+	if tree, err := ids.Parse(sequence); err == nil {
+		idx.trees[char] = tree
+	}
+}
+
+// Components returns every component of a character, at every level of its
+// decomposition and sorted: 海 (⿰氵每) has 氵, 每, 𠂉 and 母. A character whose IDS
+// is itself, or that has no IDS, has no components.
+func (idx *Index) Components(char string) []string {
+	return idx.decompose(char, make(map[string]bool))
+}
+
+// decompose finds the components of a character, skipping characters already being
+// decomposed so a cycle in the data cannot recurse forever
+func (idx *Index) decompose(char string, active map[string]bool) []string {
+	if comps, ok := idx.components[char]; ok {
+		return comps
+	}
+	active[char] = true
+	defer delete(active, char)
+
+	seen := make(map[string]bool)
+	var comps []string
+	add := func(c string) {
+		if c != char && !seen[c] {
+			seen[c] = true
+			comps = append(comps, c)
+		}
+	}
+	var parts []string
+	if tree := idx.trees[char]; tree != nil {
+		parts = tree.Components()
+	}
+	for _, part := range parts {
+		if part == char || active[part] {
+			continue
+		}
+		add(part)
+		for _, sub := range idx.decompose(part, active) {
+			add(sub)
+		}
+	}
+
+	sort.Strings(comps)
+	idx.components[char] = comps
+	return comps
+}
+
+// Characters returns the characters containing a component, ranked by stroke
+// count and then frequency
+func (idx *Index) Characters(component string) []string {
+	return idx.characters[component]
+}
+
+// All returns every component that appears in some character, sorted
+func (idx *Index) All() []string {
+	all := make([]string, 0, len(idx.characters))
+	for c := range idx.characters {
+		all = append(all, c)
+	}
+	sort.Strings(all)
+	return all
+}
+
+// Search returns the characters containing every one of the given components, such
+// as 海 for 氵 and 每, ranked with fewer strokes first, then more frequent first.
+// Characters without a known stroke count or frequency rank after those with one.
+func (idx *Index) Search(components ...string) []Match {
+	if len(components) == 0 {
+		return nil
+	}
+
+	var matches []Match
+	for _, char := range idx.characters[components[0]] {
+		if !idx.containsAll(char, components[1:]) {
+			continue
+		}
+		matches = append(matches, Match{Character: char, Strokes: idx.strokes[char], Frequency: idx.frequency[char]})
+	}
+	return matches
+}
+
+// containsAll reports whether a character has every one of the components
+func (idx *Index) containsAll(char string, components []string) bool {
+	comps := idx.Components(char)
+	for _, c := range components {
+		i := sort.SearchStrings(comps, c)
+		if i == len(comps) || comps[i] != c {
+			return false
+		}
+	}
+	return true
+}
+
+// Rank sorts characters with fewer strokes first, then more frequent first, with
+// unknown (zero) stroke counts and frequencies last. The radical picker of the
+// kradfile package ranks its kanji the same way.
+func Rank(chars []string, strokes, frequency map[string]int) {
+	sort.Slice(chars, func(i, j int) bool {
+		a, b := chars[i], chars[j]
+		if sa, sb := strokes[a], strokes[b]; sa != sb {
+			return lessKnown(sa, sb)
+		}
+		if fa, fb := frequency[a], frequency[b]; fa != fb {
+			return lessKnown(fa, fb)
+		}
+		return a < b
+	})
+}
+
+// lessKnown orders positive values ascending, with unknown (zero) values last
+func lessKnown(a, b int) bool {
+	if a == 0 || b == 0 {
+		return b == 0
+	}
+	return a < b
+}
@@ -0,0 +1,87 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/ids"
+	"kiokun-go/dictionaries/kanjidic"
+)
+
+func testIndex() *Index {
+	return FromEntries([]common.Entry{
+		ids.IDSEntry{Character: "海", IDS: "⿰氵每"},
+		ids.IDSEntry{Character: "每", IDS: "⿱𠂉母"},
+		ids.IDSEntry{Character: "梅", IDS: "⿰木每"},
+		ids.IDSEntry{Character: "悔", IDS: "⿰忄每"},
+		ids.IDSEntry{Character: "池", IDS: "⿰氵也"},
+		ids.IDSEntry{Character: "氵", IDS: "氵"},
+		ids.IDSEntry{Character: "䒑", IDS: "⿱&CDP-8958;一"},
+		kanjidic.Kanji{Character: "海", Stroke: 9, Frequency: 200},
+		kanjidic.Kanji{Character: "梅", Stroke: 10, Frequency: 1000},
+		kanjidic.Kanji{Character: "悔", Stroke: 9, Frequency: 1200},
+		kanjidic.Kanji{Character: "池", Stroke: 6, Frequency: 800},
+	})
+}
+
+func TestRegionDecomposition(t *testing.T) {
+	// 次 is written with 冫 in Japan and 二 in China
+	variants := []ids.Variant{{IDS: "⿰二欠", Regions: "G"}, {IDS: "⿰冫欠", Regions: "J"}}
+	testCases := []struct {
+		entries  []common.Entry
+		expected []string
+	}{
+		{[]common.Entry{ids.IDSEntry{Character: "次", IDS: "⿰二欠", Variants: variants}, kanjidic.Kanji{Character: "次"}}, []string{"冫", "欠"}},
+		{[]common.Entry{ids.IDSEntry{Character: "次", IDS: "⿰二欠", Variants: variants}, chinese_chars.ChineseCharEntry{Traditional: "次"}}, []string{"二", "欠"}},
+		{[]common.Entry{ids.IDSEntry{Character: "次", IDS: "⿰二欠", Variants: variants}}, []string{"二", "欠"}},
+		{[]common.Entry{ids.IDSEntry{Character: "次", IDS: "⿻⿱?"}}, nil}, // Malformed sequences are skipped
+	}
+
+	for _, tc := range testCases {
+		if got := FromEntries(tc.entries).Components("次"); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Components(次) = %v, want %v", got, tc.expected)
+		}
+	}
+}
+
+func TestComponents(t *testing.T) {
+	idx := testIndex()
+
+	if got, want := idx.Components("海"), []string{"母", "每", "氵", "𠂉"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Components(海) = %v, want %v", got, want)
+	}
+	if got, want := idx.Components("䒑"), []string{"&CDP-8958;", "一"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Components(䒑) = %v, want %v", got, want)
+	}
+	if got := idx.Components("氵"); len(got) != 0 {
+		t.Errorf("Expected no components for a primitive, got %v", got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	idx := testIndex()
+
+	testCases := []struct {
+		components []string
+		expected   []string
+	}{
+		{[]string{"氵", "每"}, []string{"海"}},
+		{[]string{"每"}, []string{"海", "悔", "梅"}}, // 9 strokes (more frequent first), then 10
+		{[]string{"氵"}, []string{"池", "海"}},
+		{[]string{"母"}, []string{"海", "悔", "梅", "每"}}, // 每 has no stroke count
+		{[]string{"氵", "木"}, nil},
+		{nil, nil},
+	}
+
+	for _, tc := range testCases {
+		var got []string
+		for _, m := range idx.Search(tc.components...) {
+			got = append(got, m.Character)
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Search(%v) = %v, want %v", tc.components, got, tc.expected)
+		}
+	}
+}
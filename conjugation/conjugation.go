@@ -0,0 +1,254 @@
+package conjugation
+
+import (
+	"strings"
+
+	"kiokun-go/dictionaries/jmdict"
+)
+
+// godanRow holds the kana used to inflect a godan verb ending
+type godanRow struct {
+	a, i, e, o string // Vowel-row kana replacing the final u-row kana
+	te, ta     string // Euphonic te/ta endings
+}
+
+// godanRows maps each godan part-of-speech class to its dictionary ending and inflection row
+var godanRows = map[jmdict.PartOfSpeech]struct {
+	ending string
+	row    godanRow
+}{
+	jmdict.V5U:   {"う", godanRow{"わ", "い", "え", "お", "って", "った"}},
+	jmdict.V5US:  {"う", godanRow{"わ", "い", "え", "お", "うて", "うた"}},
+	jmdict.V5K:   {"く", godanRow{"か", "き", "け", "こ", "いて", "いた"}},
+	jmdict.V5KS:  {"く", godanRow{"か", "き", "け", "こ", "って", "った"}},
+	jmdict.V5G:   {"ぐ", godanRow{"が", "ぎ", "げ", "ご", "いで", "いだ"}},
+	jmdict.V5S:   {"す", godanRow{"さ", "し", "せ", "そ", "して", "した"}},
+	jmdict.V5T:   {"つ", godanRow{"た", "ち", "て", "と", "って", "った"}},
+	jmdict.V5N:   {"ぬ", godanRow{"な", "に", "ね", "の", "んで", "んだ"}},
+	jmdict.V5B:   {"ぶ", godanRow{"ば", "び", "べ", "ぼ", "んで", "んだ"}},
+	jmdict.V5M:   {"む", godanRow{"ま", "み", "め", "も", "んで", "んだ"}},
+	jmdict.V5R:   {"る", godanRow{"ら", "り", "れ", "ろ", "って", "った"}},
+	jmdict.V5RI:  {"る", godanRow{"ら", "り", "れ", "ろ", "って", "った"}},
+	jmdict.V5Aru: {"る", godanRow{"ら", "い", "れ", "ろ", "って", "った"}},
+}
+
+// Conjugate builds the conjugation table for a dictionary form of the given class.
+// It returns false when the class is not conjugated or the form does not end as
+// the class requires (for example a godan-ku class on a form not ending in く).
+func Conjugate(form string, pos jmdict.PartOfSpeech) (jmdict.Conjugation, bool) {
+	c := jmdict.Conjugation{Form: form, Class: string(pos)}
+
+	if g, ok := godanRows[pos]; ok {
+		stem, ok := strings.CutSuffix(form, g.ending)
+		if !ok || stem == "" {
+			return c, false
+		}
+		r := g.row
+		c.Polite = stem + r.i + "ます"
+		c.Negative = stem + r.a + "ない"
+		c.Past = stem + r.ta
+		c.Te = stem + r.te
+		c.Potential = stem + r.e + "る"
+		c.Passive = stem + r.a + "れる"
+		c.Causative = stem + r.a + "せる"
+		c.Volitional = stem + r.o + "う"
+		c.Imperative = stem + r.e
+		c.Conditional = stem + r.e + "ば"
+
+		switch pos {
+		case jmdict.V5RI:
+			// ある: the negative is the adjective ない and there is no potential
+			c.Negative = "ない"
+			c.Potential = ""
+		case jmdict.V5Aru:
+			// くださる, いらっしゃる: imperative drops the る entirely
+			c.Imperative = stem + "い"
+		}
+		return c, true
+	}
+
+	switch pos {
+	case jmdict.V1, jmdict.V1S:
+		stem, ok := strings.CutSuffix(form, "る")
+		if !ok || stem == "" {
+			return c, false
+		}
+		c.Polite = stem + "ます"
+		c.Negative = stem + "ない"
+		c.Past = stem + "た"
+		c.Te = stem + "て"
+		c.Potential = stem + "られる"
+		c.Passive = stem + "られる"
+		c.Causative = stem + "させる"
+		c.Volitional = stem + "よう"
+		c.Imperative = stem + "ろ"
+		c.Conditional = stem + "れば"
+		if pos == jmdict.V1S {
+			// くれる: imperative is くれ
+			c.Imperative = stem
+		}
+		return c, true
+
+	case jmdict.Vk:
+		for _, kanji := range []string{"来る", "來る"} {
+			if prefix, ok := strings.CutSuffix(form, kanji); ok {
+				k := prefix + strings.TrimSuffix(kanji, "る")
+				c.Polite = k + "ます"
+				c.Negative = k + "ない"
+				c.Past = k + "た"
+				c.Te = k + "て"
+				c.Potential = k + "られる"
+				c.Passive = k + "られる"
+				c.Causative = k + "させる"
+				c.Volitional = k + "よう"
+				c.Imperative = k + "い"
+				c.Conditional = k + "れば"
+				return c, true
+			}
+		}
+		prefix, ok := strings.CutSuffix(form, "くる")
+		if !ok {
+			return c, false
+		}
+		c.Polite = prefix + "きます"
+		c.Negative = prefix + "こない"
+		c.Past = prefix + "きた"
+		c.Te = prefix + "きて"
+		c.Potential = prefix + "こられる"
+		c.Passive = prefix + "こられる"
+		c.Causative = prefix + "こさせる"
+		c.Volitional = prefix + "こよう"
+		c.Imperative = prefix + "こい"
+		c.Conditional = prefix + "くれば"
+		return c, true
+
+	case jmdict.Vs:
+		// Nouns taking する: conjugate the compound verb
+		if strings.HasSuffix(form, "する") {
+			return c, false
+		}
+		suru, ok := Conjugate(form+"する", jmdict.VsI)
+		suru.Form = form
+		suru.Class = string(pos)
+		return suru, ok
+
+	case jmdict.VsI:
+		prefix, ok := strings.CutSuffix(form, "する")
+		if !ok {
+			return c, false
+		}
+		c.Polite = prefix + "します"
+		c.Negative = prefix + "しない"
+		c.Past = prefix + "した"
+		c.Te = prefix + "して"
+		c.Potential = prefix + "できる"
+		c.Passive = prefix + "される"
+		c.Causative = prefix + "させる"
+		c.Volitional = prefix + "しよう"
+		c.Imperative = prefix + "しろ"
+		c.Conditional = prefix + "すれば"
+		return c, true
+
+	case jmdict.VsS:
+		prefix, ok := strings.CutSuffix(form, "する")
+		if !ok || prefix == "" {
+			return c, false
+		}
+		c.Polite = prefix + "します"
+		c.Negative = prefix + "さない"
+		c.Past = prefix + "した"
+		c.Te = prefix + "して"
+		c.Potential = prefix + "せる"
+		c.Passive = prefix + "される"
+		c.Causative = prefix + "させる"
+		c.Volitional = prefix + "そう"
+		c.Imperative = prefix + "せ"
+		c.Conditional = prefix + "すれば"
+		return c, true
+
+	case jmdict.Vz:
+		prefix, ok := strings.CutSuffix(form, "ずる")
+		if !ok || prefix == "" {
+			return c, false
+		}
+		c.Polite = prefix + "じます"
+		c.Negative = prefix + "じない"
+		c.Past = prefix + "じた"
+		c.Te = prefix + "じて"
+		c.Potential = prefix + "じられる"
+		c.Passive = prefix + "ぜられる"
+		c.Causative = prefix + "じさせる"
+		c.Volitional = prefix + "じよう"
+		c.Imperative = prefix + "じろ"
+		c.Conditional = prefix + "ずれば"
+		return c, true
+
+	case jmdict.AdjI, jmdict.AdjIx:
+		stem, ok := strings.CutSuffix(form, "い")
+		if !ok || stem == "" {
+			return c, false
+		}
+		if pos == jmdict.AdjIx {
+			// いい inflects from よい
+			if prefix, ok := strings.CutSuffix(form, "いい"); ok {
+				stem = prefix + "よ"
+			}
+		}
+		c.Polite = form + "です"
+		c.Negative = stem + "くない"
+		c.Past = stem + "かった"
+		c.Te = stem + "くて"
+		c.Conditional = stem + "ければ"
+		return c, true
+
+	case jmdict.AdjNa:
+		c.Polite = form + "です"
+		c.Negative = form + "じゃない"
+		c.Past = form + "だった"
+		c.Te = form + "で"
+		c.Conditional = form + "なら"
+		return c, true
+	}
+
+	return c, false
+}
+
+// ForWord returns conjugation tables for every form of a word whose senses carry
+// a conjugatable part of speech. Sense restrictions (appliesToKanji/appliesToKana)
+// are respected, so a class only applies to the forms its sense covers.
+func ForWord(w jmdict.Word) []jmdict.Conjugation {
+	var tables []jmdict.Conjugation
+	seen := make(map[string]bool)
+
+	add := func(form string, pos string) {
+		key := form + "\x00" + pos
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		if c, ok := Conjugate(form, jmdict.PartOfSpeech(pos)); ok {
+			tables = append(tables, c)
+		}
+	}
+
+	for _, sense := range w.Sense {
+		for _, pos := range sense.PartOfSpeech {
+			for _, k := range w.Kanji {
+				// A kanji form is covered when the sense applies to it with one of its readings
+				for _, kana := range w.Kana {
+					if kana.AppliesTo(k.Text) && sense.AppliesTo(k.Text, kana.Text) {
+						add(k.Text, pos)
+						break
+					}
+				}
+			}
+			for _, k := range w.Kana {
+				if sense.AppliesTo("", k.Text) {
+					add(k.Text, pos)
+				}
+			}
+		}
+	}
+
+	return tables
+}
@@ -0,0 +1,143 @@
+package conjugation
+
+import (
+	"testing"
+
+	"kiokun-go/dictionaries/jmdict"
+)
+
+func TestConjugate(t *testing.T) {
+	testCases := []struct {
+		form     string
+		pos      jmdict.PartOfSpeech
+		expected jmdict.Conjugation
+	}{
+		{"食べる", jmdict.V1, jmdict.Conjugation{
+			Polite: "食べます", Negative: "食べない", Past: "食べた", Te: "食べて",
+			Potential: "食べられる", Passive: "食べられる", Causative: "食べさせる",
+			Volitional: "食べよう", Imperative: "食べろ", Conditional: "食べれば",
+		}},
+		{"書く", jmdict.V5K, jmdict.Conjugation{
+			Polite: "書きます", Negative: "書かない", Past: "書いた", Te: "書いて",
+			Potential: "書ける", Passive: "書かれる", Causative: "書かせる",
+			Volitional: "書こう", Imperative: "書け", Conditional: "書けば",
+		}},
+		{"買う", jmdict.V5U, jmdict.Conjugation{
+			Polite: "買います", Negative: "買わない", Past: "買った", Te: "買って",
+			Potential: "買える", Passive: "買われる", Causative: "買わせる",
+			Volitional: "買おう", Imperative: "買え", Conditional: "買えば",
+		}},
+		{"行く", jmdict.V5KS, jmdict.Conjugation{
+			Polite: "行きます", Negative: "行かない", Past: "行った", Te: "行って",
+			Potential: "行ける", Passive: "行かれる", Causative: "行かせる",
+			Volitional: "行こう", Imperative: "行け", Conditional: "行けば",
+		}},
+		{"ある", jmdict.V5RI, jmdict.Conjugation{
+			Polite: "あります", Negative: "ない", Past: "あった", Te: "あって",
+			Passive: "あられる", Causative: "あらせる",
+			Volitional: "あろう", Imperative: "あれ", Conditional: "あれば",
+		}},
+		{"くださる", jmdict.V5Aru, jmdict.Conjugation{
+			Polite: "くださいます", Negative: "くださらない", Past: "くださった", Te: "くださって",
+			Potential: "くだされる", Passive: "くださられる", Causative: "くださらせる",
+			Volitional: "くださろう", Imperative: "ください", Conditional: "くだされば",
+		}},
+		{"問う", jmdict.V5US, jmdict.Conjugation{
+			Polite: "問います", Negative: "問わない", Past: "問うた", Te: "問うて",
+			Potential: "問える", Passive: "問われる", Causative: "問わせる",
+			Volitional: "問おう", Imperative: "問え", Conditional: "問えば",
+		}},
+		{"来る", jmdict.Vk, jmdict.Conjugation{
+			Polite: "来ます", Negative: "来ない", Past: "来た", Te: "来て",
+			Potential: "来られる", Passive: "来られる", Causative: "来させる",
+			Volitional: "来よう", Imperative: "来い", Conditional: "来れば",
+		}},
+		{"くる", jmdict.Vk, jmdict.Conjugation{
+			Polite: "きます", Negative: "こない", Past: "きた", Te: "きて",
+			Potential: "こられる", Passive: "こられる", Causative: "こさせる",
+			Volitional: "こよう", Imperative: "こい", Conditional: "くれば",
+		}},
+		{"する", jmdict.VsI, jmdict.Conjugation{
+			Polite: "します", Negative: "しない", Past: "した", Te: "して",
+			Potential: "できる", Passive: "される", Causative: "させる",
+			Volitional: "しよう", Imperative: "しろ", Conditional: "すれば",
+		}},
+		{"勉強", jmdict.Vs, jmdict.Conjugation{
+			Polite: "勉強します", Negative: "勉強しない", Past: "勉強した", Te: "勉強して",
+			Potential: "勉強できる", Passive: "勉強される", Causative: "勉強させる",
+			Volitional: "勉強しよう", Imperative: "勉強しろ", Conditional: "勉強すれば",
+		}},
+		{"高い", jmdict.AdjI, jmdict.Conjugation{
+			Polite: "高いです", Negative: "高くない", Past: "高かった", Te: "高くて",
+			Conditional: "高ければ",
+		}},
+		{"いい", jmdict.AdjIx, jmdict.Conjugation{
+			Polite: "いいです", Negative: "よくない", Past: "よかった", Te: "よくて",
+			Conditional: "よければ",
+		}},
+		{"静か", jmdict.AdjNa, jmdict.Conjugation{
+			Polite: "静かです", Negative: "静かじゃない", Past: "静かだった", Te: "静かで",
+			Conditional: "静かなら",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.form+"_"+string(tc.pos), func(t *testing.T) {
+			got, ok := Conjugate(tc.form, tc.pos)
+			if !ok {
+				t.Fatalf("Expected %s (%s) to conjugate", tc.form, tc.pos)
+			}
+
+			tc.expected.Form = tc.form
+			tc.expected.Class = string(tc.pos)
+			if got != tc.expected {
+				t.Errorf("Unexpected table for %s:\n got  %+v\n want %+v", tc.form, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestConjugateRejectsMismatchedForms(t *testing.T) {
+	if _, ok := Conjugate("食べる", jmdict.V5K); ok {
+		t.Errorf("Expected 食べる not to conjugate as v5k")
+	}
+	if _, ok := Conjugate("日本", jmdict.N); ok {
+		t.Errorf("Expected nouns not to conjugate")
+	}
+}
+
+func TestForWordRespectsSenseRestrictions(t *testing.T) {
+	word := jmdict.Word{
+		ID:    "1",
+		Kanji: []jmdict.KanjiEntry{{Text: "止める"}, {Text: "停める"}},
+		Kana:  []jmdict.KanaEntry{{Text: "とめる"}, {Text: "やめる"}},
+		Sense: []jmdict.Sense{
+			{PartOfSpeech: []string{"v1", "vt"}, AppliesToKanji: []string{"止める"}, AppliesToKana: []string{"やめる"}},
+		},
+	}
+
+	tables := ForWord(word)
+	if len(tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d: %+v", len(tables), tables)
+	}
+	if tables[0].Form != "止める" || tables[1].Form != "やめる" {
+		t.Errorf("Unexpected forms: %s, %s", tables[0].Form, tables[1].Form)
+	}
+}
+
+func TestForWordKanjiNeedsApplicableReading(t *testing.T) {
+	word := jmdict.Word{
+		ID:    "1",
+		Kanji: []jmdict.KanjiEntry{{Text: "止める"}, {Text: "停める"}},
+		Kana:  []jmdict.KanaEntry{{Text: "とめる"}, {Text: "やめる", AppliesToKanji: []string{"止める"}}},
+		Sense: []jmdict.Sense{
+			{PartOfSpeech: []string{"v1", "vt"}, AppliesToKana: []string{"やめる"}},
+		},
+	}
+
+	// 停める is only read とめる, which the sense does not cover
+	tables := ForWord(word)
+	if len(tables) != 2 || tables[0].Form != "止める" || tables[1].Form != "やめる" {
+		t.Errorf("Unexpected tables: %+v", tables)
+	}
+}
@@ -0,0 +1,289 @@
+package deinflect
+
+import (
+	"sort"
+	"strings"
+)
+
+// WordType is a set of word classes an inflected form can belong to
+type WordType uint
+
+const (
+	Ichidan    WordType = 1 << iota // v1: 食べる
+	Godan                           // v5*: 書く
+	Kuru                            // vk: 来る
+	Suru                            // vs*: する, 勉強する
+	IAdjective                      // adj-i: 高い
+	TeForm                          // Intermediate te-form (食べて) before いる/ある
+	surface                         // The text as written, before any rule has applied
+)
+
+// verbs is every dictionary word class
+const verbs = Ichidan | Godan | Kuru | Suru
+
+// Matches reports whether a JMdict part-of-speech code belongs to one of the types
+func (t WordType) Matches(pos string) bool {
+	switch {
+	case t&Ichidan != 0 && (pos == "v1" || pos == "v1-s"):
+		return true
+	case t&Godan != 0 && strings.HasPrefix(pos, "v5"):
+		return true
+	case t&Kuru != 0 && pos == "vk":
+		return true
+	case t&Suru != 0 && strings.HasPrefix(pos, "vs"):
+		return true
+	case t&IAdjective != 0 && (pos == "adj-i" || pos == "adj-ix"):
+		return true
+	}
+	return false
+}
+
+// Candidate is a possible dictionary form of an inflected word
+type Candidate struct {
+	Text    string   // Dictionary form (食べる)
+	Type    WordType // Word classes the dictionary form must belong to; 0 for the text as written
+	Reasons []string // Inflections removed, outermost first ("past", "negative")
+}
+
+// rule rewrites an inflected suffix back towards the dictionary form
+type rule struct {
+	from   string
+	to     string
+	in     WordType // Types the inflected form must have
+	out    WordType // Type of the result
+	reason string
+}
+
+// godanRow lists the endings of one godan row
+type godanRow struct {
+	u, i, a, e, o string // Dictionary ending and its stems
+	te, ta        string // Te-form and past endings
+}
+
+var godanRows = []godanRow{
+	{"う", "い", "わ", "え", "お", "って", "った"},
+	{"く", "き", "か", "け", "こ", "いて", "いた"},
+	{"ぐ", "ぎ", "が", "げ", "ご", "いで", "いだ"},
+	{"す", "し", "さ", "せ", "そ", "して", "した"},
+	{"つ", "ち", "た", "て", "と", "って", "った"},
+	{"ぬ", "に", "な", "ね", "の", "んで", "んだ"},
+	{"ぶ", "び", "ば", "べ", "ぼ", "んで", "んだ"},
+	{"む", "み", "ま", "め", "も", "んで", "んだ"},
+	{"る", "り", "ら", "れ", "ろ", "って", "った"},
+}
+
+// rules is the full rule table, built once
+var rules = buildRules()
+
+// buildRules expands the per-class patterns into suffix rules
+func buildRules() []rule {
+	var rs []rule
+
+	// stemRules are the suffixes that attach to a verb stem, by stem
+	type stemRule struct {
+		suffix string
+		in     WordType
+		reason string
+	}
+	iStem := []stemRule{
+		{"ます", surface, "polite"},
+		{"ました", surface, "polite past"},
+		{"ません", surface, "polite negative"},
+		{"ませんでした", surface, "polite past negative"},
+		{"ましょう", surface, "polite volitional"},
+		{"たい", surface | IAdjective, "desire"},
+		{"ながら", surface, "while"},
+		{"なさい", surface, "polite imperative"},
+	}
+	aStem := []stemRule{
+		{"ない", surface | IAdjective, "negative"},
+		{"ず", surface, "negative"},
+		{"れる", surface | Ichidan, "passive"},
+		{"せる", surface | Ichidan, "causative"},
+	}
+	eStem := []stemRule{
+		{"る", surface | Ichidan, "potential"},
+		{"ば", surface, "conditional"},
+		{"", surface, "imperative"},
+	}
+	oStem := []stemRule{
+		{"う", surface, "volitional"},
+	}
+	past := []stemRule{
+		{"", surface, "past"},
+		{"ら", surface, "conditional"},
+		{"り", surface, "tari"},
+	}
+
+	// Godan verbs
+	for _, row := range godanRows {
+		for _, s := range iStem {
+			rs = append(rs, rule{row.i + s.suffix, row.u, s.in, Godan, s.reason})
+		}
+		for _, s := range aStem {
+			rs = append(rs, rule{row.a + s.suffix, row.u, s.in, Godan, s.reason})
+		}
+		for _, s := range eStem {
+			rs = append(rs, rule{row.e + s.suffix, row.u, s.in, Godan, s.reason})
+		}
+		for _, s := range oStem {
+			rs = append(rs, rule{row.o + s.suffix, row.u, s.in, Godan, s.reason})
+		}
+		for _, s := range past {
+			rs = append(rs, rule{row.ta + s.suffix, row.u, s.in, Godan, s.reason})
+		}
+		rs = append(rs, rule{row.te, row.u, surface | TeForm, Godan, "te"})
+	}
+
+	// 行く has irregular te and past forms
+	for _, stem := range []string{"行", "い"} {
+		rs = append(rs,
+			rule{stem + "って", stem + "く", surface | TeForm, Godan, "te"},
+			rule{stem + "った", stem + "く", surface, Godan, "past"},
+			rule{stem + "ったら", stem + "く", surface, Godan, "conditional"},
+			rule{stem + "ったり", stem + "く", surface, Godan, "tari"},
+		)
+	}
+
+	// Ichidan verbs: the stem is the dictionary form without る
+	for _, s := range iStem {
+		rs = append(rs, rule{s.suffix, "る", s.in, Ichidan, s.reason})
+	}
+	rs = append(rs,
+		rule{"ない", "る", surface | IAdjective, Ichidan, "negative"},
+		rule{"ず", "る", surface, Ichidan, "negative"},
+		rule{"られる", "る", surface | Ichidan, Ichidan, "passive or potential"},
+		rule{"れる", "る", surface | Ichidan, Ichidan, "potential"},
+		rule{"させる", "る", surface | Ichidan, Ichidan, "causative"},
+		rule{"れば", "る", surface, Ichidan, "conditional"},
+		rule{"よう", "る", surface, Ichidan, "volitional"},
+		rule{"ろ", "る", surface, Ichidan, "imperative"},
+		rule{"よ", "る", surface, Ichidan, "imperative"},
+		rule{"た", "る", surface, Ichidan, "past"},
+		rule{"たら", "る", surface, Ichidan, "conditional"},
+		rule{"たり", "る", surface, Ichidan, "tari"},
+		rule{"て", "る", surface | TeForm, Ichidan, "te"},
+	)
+
+	// する and 来る, in kana and (for 来る) kanji
+	irregular := []struct {
+		dict  string
+		forms map[string]string // Inflected form → reason
+		in    map[string]WordType
+		out   WordType
+	}{
+		{"する", map[string]string{
+			"します": "polite", "しました": "polite past", "しません": "polite negative",
+			"しない": "negative", "せず": "negative", "した": "past", "したら": "conditional",
+			"して": "te", "される": "passive", "させる": "causative", "できる": "potential",
+			"しよう": "volitional", "しろ": "imperative", "せよ": "imperative", "すれば": "conditional",
+			"したい": "desire",
+		}, map[string]WordType{"しない": IAdjective, "したい": IAdjective, "して": TeForm,
+			"される": Ichidan, "させる": Ichidan, "できる": Ichidan}, Suru},
+		{"くる", map[string]string{
+			"きます": "polite", "きました": "polite past", "きません": "polite negative",
+			"こない": "negative", "きた": "past", "きたら": "conditional", "きて": "te",
+			"こられる": "passive or potential", "こさせる": "causative", "こよう": "volitional",
+			"こい": "imperative", "くれば": "conditional", "きたい": "desire",
+		}, map[string]WordType{"こない": IAdjective, "きたい": IAdjective, "きて": TeForm,
+			"こられる": Ichidan, "こさせる": Ichidan}, Kuru},
+		{"来る", map[string]string{
+			"来ます": "polite", "来ました": "polite past", "来ません": "polite negative",
+			"来ない": "negative", "来た": "past", "来たら": "conditional", "来て": "te",
+			"来られる": "passive or potential", "来させる": "causative", "来よう": "volitional",
+			"来い": "imperative", "来れば": "conditional", "来たい": "desire",
+		}, map[string]WordType{"来ない": IAdjective, "来たい": IAdjective, "来て": TeForm,
+			"来られる": Ichidan, "来させる": Ichidan}, Kuru},
+	}
+	for _, irr := range irregular {
+		forms := make([]string, 0, len(irr.forms))
+		for form := range irr.forms {
+			forms = append(forms, form)
+		}
+		sort.Strings(forms)
+		for _, form := range forms {
+			rs = append(rs, rule{form, irr.dict, surface | irr.in[form], irr.out, irr.forms[form]})
+		}
+	}
+
+	// Progressive and resultative forms continue from the te-form
+	rs = append(rs,
+		rule{"ている", "て", surface | Ichidan, TeForm, "progressive"},
+		rule{"てる", "て", surface | Ichidan, TeForm, "progressive"},
+		rule{"でいる", "で", surface | Ichidan, TeForm, "progressive"},
+		rule{"でる", "で", surface | Ichidan, TeForm, "progressive"},
+		rule{"てある", "て", surface | Godan, TeForm, "resultative"},
+		rule{"てしまう", "て", surface | Godan, TeForm, "completion"},
+		rule{"でしまう", "で", surface | Godan, TeForm, "completion"},
+		rule{"ちゃう", "て", surface | Godan, TeForm, "completion"},
+		rule{"じゃう", "で", surface | Godan, TeForm, "completion"},
+	)
+
+	// I-adjectives
+	rs = append(rs,
+		rule{"かった", "い", surface, IAdjective, "past"},
+		rule{"くない", "い", surface | IAdjective, IAdjective, "negative"},
+		rule{"くて", "い", surface, IAdjective, "te"},
+		rule{"く", "い", surface, IAdjective, "adverbial"},
+		rule{"ければ", "い", surface, IAdjective, "conditional"},
+		rule{"かったら", "い", surface, IAdjective, "conditional"},
+		rule{"さ", "い", surface, IAdjective, "noun"},
+		rule{"そう", "い", surface, IAdjective, "seemingness"},
+		rule{"すぎる", "い", surface | Ichidan, IAdjective, "excess"},
+	)
+
+	return rs
+}
+
+// maxDepth bounds how many inflections are peeled off one word
+const maxDepth = 6
+
+// Deinflect returns every candidate dictionary form of an inflected word, starting
+// with the text itself (Type 0, no reasons). Candidates are not checked against a
+// dictionary; a caller should keep only those whose entry has a part of speech
+// matching the candidate's Type.
+func Deinflect(text string) []Candidate {
+	results := []Candidate{{Text: text}}
+
+	type seenKey struct {
+		text string
+		typ  WordType
+	}
+	seen := make(map[seenKey]bool)
+
+	type state struct {
+		text    string
+		typ     WordType
+		reasons []string
+	}
+	queue := []state{{text, surface | verbs | IAdjective | TeForm, nil}}
+
+	for depth := 0; depth < maxDepth && len(queue) > 0; depth++ {
+		var next []state
+		for _, s := range queue {
+			for _, r := range rules {
+				if s.typ&r.in == 0 || !strings.HasSuffix(s.text, r.from) {
+					continue
+				}
+				base := strings.TrimSuffix(s.text, r.from) + r.to
+				if base == "" || base == s.text {
+					continue
+				}
+				key := seenKey{base, r.out}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				reasons := append(append([]string{}, s.reasons...), r.reason)
+				next = append(next, state{base, r.out, reasons})
+				if r.out != TeForm {
+					results = append(results, Candidate{Text: base, Type: r.out, Reasons: reasons})
+				}
+			}
+		}
+		queue = next
+	}
+
+	return results
+}
@@ -0,0 +1,63 @@
+package deinflect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeinflect(t *testing.T) {
+	testCases := []struct {
+		word     string
+		base     string
+		typ      WordType
+		pos      string
+		expected []string
+	}{
+		{"食べました", "食べる", Ichidan, "v1", []string{"polite past"}},
+		{"食べなかった", "食べる", Ichidan, "v1", []string{"past", "negative"}},
+		{"食べている", "食べる", Ichidan, "v1", []string{"progressive", "te"}},
+		{"食べられる", "食べる", Ichidan, "v1", []string{"passive or potential"}},
+		{"書いた", "書く", Godan, "v5k", []string{"past"}},
+		{"書かれた", "書く", Godan, "v5k", []string{"past", "passive"}},
+		{"読んで", "読む", Godan, "v5m", []string{"te"}},
+		{"行った", "行く", Godan, "v5k-s", []string{"past"}},
+		{"泳げる", "泳ぐ", Godan, "v5g", []string{"potential"}},
+		{"勉強しました", "勉強する", Suru, "vs-i", []string{"polite past"}},
+		{"来ない", "来る", Kuru, "vk", []string{"negative"}},
+		{"きて", "くる", Kuru, "vk", []string{"te"}},
+		{"高かった", "高い", IAdjective, "adj-i", []string{"past"}},
+		{"高くない", "高い", IAdjective, "adj-i", []string{"negative"}},
+		{"食べたくない", "食べる", Ichidan, "v1", []string{"negative", "desire"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.word, func(t *testing.T) {
+			for _, c := range Deinflect(tc.word) {
+				if c.Text != tc.base || c.Type != tc.typ {
+					continue
+				}
+				if !c.Type.Matches(tc.pos) {
+					t.Errorf("Type %v does not match %s", c.Type, tc.pos)
+				}
+				if !reflect.DeepEqual(c.Reasons, tc.expected) {
+					t.Errorf("Reasons = %v, want %v", c.Reasons, tc.expected)
+				}
+				return
+			}
+			t.Errorf("Deinflect(%s) did not produce %s", tc.word, tc.base)
+		})
+	}
+}
+
+func TestDeinflectKeepsOriginal(t *testing.T) {
+	candidates := Deinflect("日本")
+	if len(candidates) == 0 || candidates[0].Text != "日本" || candidates[0].Type != 0 {
+		t.Fatalf("Expected the original text first, got %+v", candidates)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	if Godan.Matches("v1") || !Godan.Matches("v5u") || !Suru.Matches("vs-s") || IAdjective.Matches("adj-na") {
+		t.Error("Unexpected part-of-speech match")
+	}
+}
@@ -0,0 +1,242 @@
+package cedict
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"kiokun-go/dictionaries/chinese_words"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/pinyin"
+)
+
+// Source is the Item source of every reading imported from CC-CEDICT
+const Source = "cedict"
+
+// Importer handles importing CC-CEDICT
+type Importer struct{}
+
+// Name returns the name of this importer
+func (i *Importer) Name() string {
+	return "cedict"
+}
+
+// Import reads CC-CEDICT into Chinese word entries. A path with a glob pattern
+// imports the last matching file in name order; zip archives are skipped and
+// .gz files are decompressed.
+func (i *Importer) Import(path string) ([]common.Entry, error) {
+	paths, err := filepath.Glob(path)
+	if err != nil {
+		return nil, err
+	}
+	var source string
+	for _, p := range paths {
+		if !strings.HasSuffix(p, ".zip") && p > source {
+			source = p
+		}
+	}
+	if source == "" {
+		return nil, fmt.Errorf("no CC-CEDICT file matches %s", path)
+	}
+
+	file, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(source, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("error decompressing %s: %v", source, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	words, err := Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", source, err)
+	}
+	entries := make([]common.Entry, len(words))
+	for i, w := range words {
+		entries[i] = w
+	}
+	return entries, nil
+}
+
+// lineRegex matches an entry line: Trad Simp [pin1 yin1] /def/def/
+var lineRegex = regexp.MustCompile(`^(\S+) (\S+) \[([^\]]*)\] /(.*)/\s*$`)
+
+// Parse reads CC-CEDICT lines into Chinese word entries, one per traditional and
+// simplified pair with an item for each line, in line order. Lines starting with #
+// are comments. Entries are sorted by traditional form.
+func Parse(r io.Reader) ([]chinese_words.ChineseWordEntry, error) {
+	var keys []string
+	words := make(map[string]*chinese_words.ChineseWordEntry)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		m := lineRegex.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("line %d: not a CC-CEDICT entry: %q", lineNum, line)
+		}
+
+		key := m[1] + "\t" + m[2]
+		word, ok := words[key]
+		if !ok {
+			word = &chinese_words.ChineseWordEntry{Traditional: m[1], Simplified: m[2]}
+			words[key] = word
+			keys = append(keys, key)
+		}
+		word.Items = append(word.Items, parseItem(m[3], m[4]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(keys)
+	result := make([]chinese_words.ChineseWordEntry, len(keys))
+	used := make(map[string]bool, len(keys))
+	for i, key := range keys {
+		word := words[key]
+		word.ID = stableID(key, used)
+
+		// Project the items onto the flat lists older clients read
+		word.Pinyin = word.Readings()
+		word.Definitions = word.ItemDefinitions()
+		result[i] = *word
+	}
+	return result, nil
+}
+
+// idOffset keeps CC-CEDICT IDs in the Chinese word range, above the sequential IDs of
+// the dong-chinese dump
+const idOffset = 4000000000000
+
+// stableID derives a word's ID from its forms, so it stays the same when other words
+// are added or removed. A hash collision moves the later key in sort order to the
+// next free ID.
+func stableID(key string, used map[string]bool) string {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	n := h.Sum64() % 1000000000000
+	for {
+		id := strconv.FormatUint(idOffset+n, 10)
+		if !used[id] {
+			used[id] = true
+			return id
+		}
+		n = (n + 1) % 1000000000000
+	}
+}
+
+// parseItem turns the pinyin and definitions of a line into an item, moving CL:
+// classifier lists to Classifiers and collecting variant and see-also references.
+// Classifiers are kept as bare traditional words, as in the dong-chinese dump.
+func parseItem(reading, definitions string) chinese_words.Item {
+	item := chinese_words.Item{Source: Source, Pinyin: diacritic(reading)}
+	for _, def := range strings.Split(definitions, "/") {
+		def = strings.TrimSpace(def)
+		if def == "" {
+			continue
+		}
+		if list, ok := strings.CutPrefix(def, "CL:"); ok {
+			for _, cl := range strings.Split(list, ",") {
+				if r := refRegex.FindStringSubmatch(strings.TrimSpace(cl)); r != nil {
+					item.Classifiers = append(item.Classifiers, r[1])
+				}
+			}
+			continue
+		}
+		item.Definitions = append(item.Definitions, def)
+		item.Refs = append(item.Refs, parseRefs(def)...)
+	}
+	return item
+}
+
+// refPhraseRegex matches the phrase that introduces references in a definition:
+// "variant of", "old variant of", "see", "see also", ...
+var refPhraseRegex = regexp.MustCompile(`^(?:((?:[a-zA-Z]+ )*variant) of|(see(?: also)?)) `)
+
+// refRegex matches a reference such as 個|个[ge4], 个[ge4] or 個|个
+var refRegex = regexp.MustCompile(`^([^\s,|\[\]]+)(?:\|([^\s,|\[\]]+))?(?:\[([^\]]*)\])?`)
+
+// parseRefs returns the references of a definition such as "variant of 個|个[ge4]" or
+// "see also 某|某[mou3] and 某些[mou3 xie1]"
+func parseRefs(def string) []chinese_words.Ref {
+	m := refPhraseRegex.FindStringSubmatch(def)
+	if m == nil {
+		return nil
+	}
+	refType := m[1] + m[2]
+	rest := def[len(m[0]):]
+
+	var refs []chinese_words.Ref
+	for {
+		r := refRegex.FindStringSubmatch(rest)
+		if r == nil || !startsWithHan(r[1]) {
+			break
+		}
+		ref := chinese_words.Ref{Type: refType, Traditional: r[1], Simplified: r[2], Pinyin: diacritic(r[3])}
+		if ref.Simplified == "" {
+			ref.Simplified = ref.Traditional
+		}
+		refs = append(refs, ref)
+
+		rest = rest[len(r[0]):]
+		sep := ""
+		for _, s := range []string{", ", ",", " and ", " or "} {
+			if strings.HasPrefix(rest, s) {
+				sep = s
+				break
+			}
+		}
+		if sep == "" {
+			break
+		}
+		rest = rest[len(sep):]
+	}
+	return refs
+}
+
+// startsWithHan reports whether a string starts with a Han character
+func startsWithHan(s string) bool {
+	for _, r := range s {
+		return unicode.Is(unicode.Han, r)
+	}
+	return false
+}
+
+// diacritic converts CC-CEDICT numbered pinyin to tone marks one syllable at a time,
+// keeping the spaces and the capitals of proper nouns: "Bei3 jing1" → "Běi jīng".
+// Syllables that are not pinyin, such as letters and punctuation, are kept as is.
+func diacritic(reading string) string {
+	syllables := strings.Fields(reading)
+	for i, s := range syllables {
+		marked := pinyin.ToDiacritic(s)
+		if marked != s && unicode.IsUpper([]rune(s)[0]) {
+			r := []rune(marked)
+			r[0] = unicode.ToUpper(r[0])
+			marked = string(r)
+		}
+		syllables[i] = marked
+	}
+	return strings.Join(syllables, " ")
+}
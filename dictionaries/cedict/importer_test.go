@@ -0,0 +1,142 @@
+package cedict
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"kiokun-go/dictionaries/chinese_words"
+)
+
+// testCEDICT is an abridged CC-CEDICT file
+const testCEDICT = `# CC-CEDICT
+#! version=1
+#! charset=UTF-8
+個 个 [ge4] /individual/this/that/size/classifier for people or objects in general/
+行 行 [hang2] /row/line/commercial firm/CL:排[pai2],條|条[tiao2]/
+行 行 [xing2] /to walk/to go/capable/
+北京 北京 [Bei3 jing1] /Beijing, capital of the People's Republic of China/
+箇 个 [ge4] /variant of 個|个[ge4]/
+喂 喂 [wei4] /see also 餵|喂[wei4] and 哦[o4]/hello (when answering the phone)/
+卡拉OK 卡拉OK [ka3 la1 O K] /karaoke (loanword)/
+`
+
+func TestParse(t *testing.T) {
+	words, err := Parse(strings.NewReader(testCEDICT))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	byForm := make(map[string]chinese_words.ChineseWordEntry)
+	for _, w := range words {
+		byForm[w.Traditional] = w
+	}
+	if len(words) != 6 {
+		t.Fatalf("Expected 6 words, got %d", len(words))
+	}
+
+	// Both readings of 行 are items of one word, with their own definitions
+	walk := byForm["行"]
+	if len(walk.Items) != 2 || walk.Items[0].Pinyin != "háng" || walk.Items[1].Pinyin != "xíng" {
+		t.Fatalf("Expected two readings of 行, got %+v", walk.Items)
+	}
+	if !reflect.DeepEqual(walk.Items[0].Definitions, []string{"row", "line", "commercial firm"}) {
+		t.Errorf("Unexpected definitions for háng: %v", walk.Items[0].Definitions)
+	}
+	if !reflect.DeepEqual(walk.Items[0].Classifiers, []string{"排", "條"}) {
+		t.Errorf("Unexpected classifiers: %v", walk.Items[0].Classifiers)
+	}
+	if !reflect.DeepEqual(walk.Pinyin, []string{"háng", "xíng"}) || len(walk.Definitions) != 6 || walk.Items[1].Source != Source {
+		t.Errorf("Unexpected legacy projection for 行: %v %v", walk.Pinyin, walk.Definitions)
+	}
+
+	if got := byForm["北京"].Items[0].Pinyin; got != "Běi jīng" {
+		t.Errorf("Expected proper noun capitals kept, got %q", got)
+	}
+	if got := byForm["卡拉OK"].Items[0].Pinyin; got != "kǎ lā O K" {
+		t.Errorf("Expected letters kept, got %q", got)
+	}
+
+	variant := byForm["箇"]
+	if variant.Simplified != "个" || !reflect.DeepEqual(variant.Items[0].Refs, []chinese_words.Ref{{Type: "variant", Traditional: "個", Simplified: "个", Pinyin: "gè"}}) {
+		t.Errorf("Unexpected variant reference: %+v", variant.Items[0].Refs)
+	}
+	want := []chinese_words.Ref{
+		{Type: "see also", Traditional: "餵", Simplified: "喂", Pinyin: "wèi"},
+		{Type: "see also", Traditional: "哦", Simplified: "哦", Pinyin: "ò"},
+	}
+	if refs := byForm["喂"].Items[0].Refs; !reflect.DeepEqual(refs, want) {
+		t.Errorf("Unexpected see also references: %+v", refs)
+	}
+}
+
+func TestParseRefs(t *testing.T) {
+	tests := []struct {
+		def  string
+		want []chinese_words.Ref
+	}{
+		{"old variant of 個|个[ge4], classifier", []chinese_words.Ref{{Type: "old variant", Traditional: "個", Simplified: "个", Pinyin: "gè"}}},
+		{"see 某[mou3]", []chinese_words.Ref{{Type: "see", Traditional: "某", Simplified: "某", Pinyin: "mǒu"}}},
+		{"variant of 個|个", []chinese_words.Ref{{Type: "variant", Traditional: "個", Simplified: "个"}}},
+		{"see you later", nil},
+		{"to walk", nil},
+	}
+	for _, tt := range tests {
+		if got := parseRefs(tt.def); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseRefs(%q) = %+v, want %+v", tt.def, got, tt.want)
+		}
+	}
+}
+
+func TestStableIDs(t *testing.T) {
+	before, err := Parse(strings.NewReader(testCEDICT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := Parse(strings.NewReader("一 一 [yi1] /one/\n" + testCEDICT))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make(map[string]string)
+	for _, w := range before {
+		ids[w.Traditional] = w.ID
+	}
+	for _, w := range after {
+		if id, ok := ids[w.Traditional]; ok && id != w.ID {
+			t.Errorf("ID of %s changed from %s to %s when a word was added", w.Traditional, id, w.ID)
+		}
+	}
+}
+
+func TestImportGzip(t *testing.T) {
+	dir := t.TempDir()
+	file, err := os.Create(filepath.Join(dir, "cedict_1_0_ts_utf-8_mdbg.txt.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(file)
+	gz.Write([]byte(testCEDICT))
+	gz.Close()
+	file.Close()
+
+	entries, err := (&Importer{}).Import(filepath.Join(dir, "cedict*"))
+	if err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+	if len(entries) != 6 {
+		t.Errorf("Expected 6 entries, got %d", len(entries))
+	}
+
+	if _, err := (&Importer{}).Import(filepath.Join(t.TempDir(), "cedict*")); err == nil {
+		t.Error("Expected an error without a CC-CEDICT file")
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	if _, err := Parse(strings.NewReader("# comment\n個 个 ge4 /individual/\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error for line 2, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+package cedict
+
+import (
+	"kiokun-go/dictionaries/common"
+)
+
+func init() {
+	// Register CC-CEDICT, distributed as cedict_ts.u8 or cedict_1_0_ts_utf-8_mdbg.txt(.gz)
+	common.RegisterDictionary("cedict", "cedict*", &Importer{})
+}
@@ -1,8 +1,10 @@
 package chinese_chars
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 
@@ -17,6 +19,26 @@ func (i *Importer) Name() string {
 	return "chinese_chars"
 }
 
+// rawChar is a character as written in the dong-chinese dump
+type rawChar struct {
+	ID                string             `json:"_id"`
+	Char              string             `json:"char"`
+	Gloss             string             `json:"gloss"`
+	StrokeCount       int                `json:"strokeCount"`
+	SimpVariants      []string           `json:"simpVariants"`
+	TradVariants      []string           `json:"tradVariants"`
+	VariantOf         string             `json:"variantOf"`
+	Variants          []Variant          `json:"variants"`
+	Components        []Component        `json:"components"`
+	PinyinFrequencies []PinyinFrequency  `json:"pinyinFrequencies"`
+	OldPronunciations []OldPronunciation `json:"oldPronunciations"`
+	Hint              string             `json:"hint"`
+	OriginalMeaning   string             `json:"originalMeaning"`
+	Shuowen           string             `json:"shuowen"`
+	Images            []Image            `json:"images"`
+	Statistics        *Statistics        `json:"statistics"`
+}
+
 // Import reads and processes the Chinese character dictionary
 func (i *Importer) Import(path string) ([]common.Entry, error) {
 	file, err := os.Open(path)
@@ -25,54 +47,40 @@ func (i *Importer) Import(path string) ([]common.Entry, error) {
 	}
 	defer file.Close()
 
-	// Parse the JSON file as a generic map to handle MongoDB-style fields
-	var rawEntries []map[string]interface{}
-	if err := json.NewDecoder(file).Decode(&rawEntries); err != nil {
-		return nil, err
+	rawEntries, err := decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
 	}
 
 	// Create a slice of entries for sorting
 	tempEntries := make([]ChineseCharEntry, len(rawEntries))
-	for i, rawEntry := range rawEntries {
-		entry := ChineseCharEntry{}
-
-		// Map _id to ID (we'll replace this with sequential IDs later)
-		if id, ok := rawEntry["_id"]; ok {
-			if idStr, ok := id.(string); ok {
-				entry.ID = idStr
-			}
-		}
-
-		// Map char to Traditional
-		if char, ok := rawEntry["char"]; ok {
-			if charStr, ok := char.(string); ok {
-				entry.Traditional = charStr
-				// If no simplified form is specified, use the traditional form
-				entry.Simplified = charStr
-			}
-		}
-
-		// Map simplified if available
-		if simp, ok := rawEntry["simpVariants"]; ok {
-			if simpArr, ok := simp.([]interface{}); ok && len(simpArr) > 0 {
-				if simpStr, ok := simpArr[0].(string); ok {
-					entry.Simplified = simpStr
-				}
-			}
+	for i, raw := range rawEntries {
+		// The ID is replaced with a sequential ID below. If no simplified form is
+		// specified, the character is its own simplified form.
+		entry := ChineseCharEntry{
+			ID:                raw.ID,
+			Traditional:       raw.Char,
+			Simplified:        raw.Char,
+			StrokeCount:       raw.StrokeCount,
+			SimpVariants:      raw.SimpVariants,
+			TradVariants:      raw.TradVariants,
+			VariantOf:         raw.VariantOf,
+			Variants:          raw.Variants,
+			Components:        raw.Components,
+			PinyinFrequencies: raw.PinyinFrequencies,
+			OldPronunciations: raw.OldPronunciations,
+			Hint:              raw.Hint,
+			OriginalMeaning:   raw.OriginalMeaning,
+			Shuowen:           raw.Shuowen,
+			Images:            raw.Images,
+			Statistics:        raw.Statistics,
+			Pinyin:            readings(raw),
 		}
-
-		// Map gloss to Definitions
-		if gloss, ok := rawEntry["gloss"]; ok {
-			if glossStr, ok := gloss.(string); ok {
-				entry.Definitions = []string{glossStr}
-			}
+		if len(raw.SimpVariants) > 0 {
+			entry.Simplified = raw.SimpVariants[0]
 		}
-
-		// Map strokeCount
-		if stroke, ok := rawEntry["strokeCount"]; ok {
-			if strokeFloat, ok := stroke.(float64); ok {
-				entry.StrokeCount = int(strokeFloat)
-			}
+		if raw.Gloss != "" {
+			entry.Definitions = []string{raw.Gloss}
 		}
 
 		// Ensure ID is set
@@ -104,3 +112,74 @@ func (i *Importer) Import(path string) ([]common.Entry, error) {
 
 	return entries, nil
 }
+
+// decode reads the characters of a dong-chinese dump, either a JSON array or JSON
+// lines with one character per line
+func decode(r io.Reader) ([]rawChar, error) {
+	reader := bufio.NewReader(r)
+	decoder := json.NewDecoder(reader)
+
+	// Peek past leading whitespace to tell an array from JSON lines
+	for {
+		b, err := reader.Peek(1)
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n' {
+			if b[0] == '[' {
+				if _, err := decoder.Token(); err != nil {
+					return nil, err
+				}
+			}
+			break
+		}
+		reader.ReadByte()
+	}
+
+	var chars []rawChar
+	for decoder.More() {
+		var c rawChar
+		if err := decoder.Decode(&c); err != nil {
+			return nil, fmt.Errorf("character %d: %v", len(chars)+1, err)
+		}
+		chars = append(chars, c)
+	}
+	return chars, nil
+}
+
+// readings returns the pinyin readings of a character, most used first, falling back
+// to the readings of its old pronunciations when it has no frequencies
+func readings(c rawChar) []string {
+	freqs := make([]PinyinFrequency, len(c.PinyinFrequencies))
+	copy(freqs, c.PinyinFrequencies)
+	sort.SliceStable(freqs, func(i, j int) bool {
+		return freqs[i].Count > freqs[j].Count
+	})
+
+	var result []string
+	for _, f := range freqs {
+		result = appendReading(result, f.Pinyin)
+	}
+	if len(result) == 0 {
+		for _, p := range c.OldPronunciations {
+			result = appendReading(result, p.Pinyin)
+		}
+	}
+	return result
+}
+
+// appendReading appends a reading unless it is empty or already listed
+func appendReading(readings []string, reading string) []string {
+	if reading == "" {
+		return readings
+	}
+	for _, r := range readings {
+		if r == reading {
+			return readings
+		}
+	}
+	return append(readings, reading)
+}
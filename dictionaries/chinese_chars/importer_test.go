@@ -0,0 +1,74 @@
+package chinese_chars
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testDump is an abridged dong-chinese character dump, one character per line
+const testDump = `{"_id":"5f1","char":"好","codepoint":"U+597D","strokeCount":6,"gloss":"good","hint":"A woman 女 with a child 子","originalMeaning":"beautiful","components":[{"character":"女","type":["meaning"],"hint":"woman"},{"character":"子","type":"meaning"}],"pinyinFrequencies":[{"pinyin":"hào","count":120},{"pinyin":"hǎo","count":9800}],"oldPronunciations":[{"pinyin":"hǎo","MC":"xawX","OC":"*qʰˤuʔ","source":"baxter-sagart"}],"images":[{"source":"Xiaoxuetang","url":"https://example.com/hao.png","type":"Oracle","era":"Shang"}],"statistics":{"hskLevel":1,"movieCharRank":12,"bookCharCountPercent":0.25,"topWords":[{"word":"好","share":0.4,"gloss":"good"}]}}
+{"_id":"5f2","char":"們","strokeCount":10,"gloss":"plural marker","simpVariants":["们"],"components":[{"character":"亻","type":["meaning"]},{"character":"門","type":["sound"],"isGlyphChanged":false}],"oldPronunciations":[{"pinyin":"mén","source":"zhengzhang"}],"statistics":null}
+`
+
+func TestImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dictionary_char_test.jsonl")
+	if err := os.WriteFile(path, []byte(testDump), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := (&Importer{}).Import(path)
+	if err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	// Entries are sorted by character (們 U+5011 before 好 U+597D) and numbered from 3000001
+	good := entries[1].(ChineseCharEntry)
+	if good.ID != "3000002" || good.Traditional != "好" || good.Simplified != "好" || good.StrokeCount != 6 {
+		t.Errorf("Unexpected entry for 好: %+v", good)
+	}
+	if !reflect.DeepEqual(good.Pinyin, []string{"hǎo", "hào"}) {
+		t.Errorf("Expected readings most used first, got %v", good.Pinyin)
+	}
+	if len(good.Components) != 2 || !reflect.DeepEqual(good.Components[1].Type, Strings{"meaning"}) || good.Components[0].Hint != "woman" {
+		t.Errorf("Unexpected components for 好: %+v", good.Components)
+	}
+	if good.Hint == "" || good.OriginalMeaning != "beautiful" || good.OldPronunciations[0].OC != "*qʰˤuʔ" {
+		t.Errorf("Unexpected etymology for 好: %+v", good)
+	}
+	if len(good.Images) != 1 || good.Images[0].Type != "Oracle" {
+		t.Errorf("Unexpected images for 好: %+v", good.Images)
+	}
+	if s := good.Statistics; s == nil || s.HskLevel != 1 || s.MovieCharRank != 12 || s.BookCharCountPercent != 0.25 || s.TopWords[0].Word != "好" {
+		t.Errorf("Unexpected statistics for 好: %+v", s)
+	}
+
+	// Without pinyin frequencies the readings come from the old pronunciations
+	plural := entries[0].(ChineseCharEntry)
+	if plural.Simplified != "们" || !reflect.DeepEqual(plural.Pinyin, []string{"mén"}) || plural.Statistics != nil {
+		t.Errorf("Unexpected entry for 們: %+v", plural)
+	}
+}
+
+func TestImportJSONArray(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dictionary_char_test.json")
+	dump := "\n [" + `{"_id":"1","char":"水","pinyinFrequencies":[{"pinyin":"shuǐ","count":5}]}, {"_id":"2","char":"火"}` + "]\n"
+	if err := os.WriteFile(path, []byte(dump), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := (&Importer{}).Import(path)
+	if err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if water := entries[0].(ChineseCharEntry); water.Traditional != "水" || !reflect.DeepEqual(water.Pinyin, []string{"shuǐ"}) {
+		t.Errorf("Unexpected entry for 水: %+v", water)
+	}
+}
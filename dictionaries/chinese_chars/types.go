@@ -1,14 +1,137 @@
 package chinese_chars
 
+import "encoding/json"
+
 // ChineseCharEntry represents a single Chinese character entry
 type ChineseCharEntry struct {
-	ID          string   `json:"id"`
-	Traditional string   `json:"traditional"`
-	Simplified  string   `json:"simplified"`
-	Definitions []string `json:"definitions,omitempty"`
-	Pinyin      []string `json:"pinyin,omitempty"`
-	StrokeCount int      `json:"strokeCount,omitempty"`
-	IDS         string   `json:"ids,omitempty"` // Ideographic Description Sequence
+	ID           string   `json:"id"`
+	Traditional  string   `json:"traditional"`
+	Simplified   string   `json:"simplified"`
+	Definitions  []string `json:"definitions,omitempty"`
+	Pinyin       []string `json:"pinyin,omitempty"`
+	StrokeCount  int      `json:"strokeCount,omitempty"`
+	IDS          string   `json:"ids,omitempty"`          // Ideographic Description Sequence
+	SimpVariants []string `json:"simpVariants,omitempty"` // Every simplified form
+	TradVariants []string `json:"tradVariants,omitempty"` // Traditional forms, for simplified characters
+
+	// From the dong-chinese dump
+	VariantOf         string             `json:"variantOf,omitempty"` // Character this one is a variant of
+	Variants          []Variant          `json:"variants,omitempty"`
+	Components        []Component        `json:"components,omitempty"`
+	PinyinFrequencies []PinyinFrequency  `json:"pinyinFrequencies,omitempty"`
+	OldPronunciations []OldPronunciation `json:"oldPronunciations,omitempty"`
+	Hint              string             `json:"hint,omitempty"`            // How the components give the meaning
+	OriginalMeaning   string             `json:"originalMeaning,omitempty"` // Meaning the character was created for
+	Shuowen           string             `json:"shuowen,omitempty"`         // Shuowen Jiezi explanation
+	Images            []Image            `json:"images,omitempty"`          // Historical forms
+	Statistics        *Statistics        `json:"statistics,omitempty"`
+
+	// From Make Me a Hanzi, when it has the character
+	Decomposition string     `json:"decomposition,omitempty"` // IDS with ？ for unknown components, to reconcile with IDS
+	Radical       string     `json:"radical,omitempty"`       // Radical as written in the character, such as 氵
+	Etymology     *Etymology `json:"etymology,omitempty"`
+}
+
+// Component is a component of a character and the role it plays
+type Component struct {
+	Character             string  `json:"character"`
+	Type                  Strings `json:"type,omitempty"` // meaning, sound, iconic, simplified, remnant, distinguishing, deleted or unknown
+	Hint                  string  `json:"hint,omitempty"`
+	IsOldPronunciation    bool    `json:"isOldPronunciation,omitempty"`    // Sound component only in old pronunciations
+	IsGlyphChanged        bool    `json:"isGlyphChanged,omitempty"`        // Written differently than the original component
+	IsFromOriginalMeaning bool    `json:"isFromOriginalMeaning,omitempty"` // Meaning component for the original meaning
+}
+
+// Variant is a variant form of a character
+type Variant struct {
+	Char   string `json:"char,omitempty"`
+	Parts  string `json:"parts,omitempty"`  // Components, for variants without a code point
+	Source string `json:"source,omitempty"` // Where the variant is listed
+}
+
+// PinyinFrequency is how often a reading of a character is used
+type PinyinFrequency struct {
+	Pinyin string `json:"pinyin"`
+	Count  int    `json:"count"`
+}
+
+// OldPronunciation is a reconstructed Middle and Old Chinese reading
+type OldPronunciation struct {
+	Pinyin string `json:"pinyin,omitempty"`
+	MC     string `json:"MC,omitempty"`     // Middle Chinese
+	OC     string `json:"OC,omitempty"`     // Old Chinese
+	Gloss  string `json:"gloss,omitempty"`  // Meaning the reading is for
+	Source string `json:"source,omitempty"` // Reconstruction, such as baxter-sagart or zhengzhang
+}
+
+// Image is a historical form of a character
+type Image struct {
+	Source      string `json:"source,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Type        string `json:"type,omitempty"` // Script, such as Oracle, Bronze, Seal or Clerical
+	Era         string `json:"era,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Statistics is how often a character is used in film subtitles and books, alone
+// and as a one-character word. Percentages are of all characters or words.
+type Statistics struct {
+	HskLevel                 int       `json:"hskLevel,omitempty"`
+	TopWords                 []TopWord `json:"topWords,omitempty"`
+	MovieCharCount           int       `json:"movieCharCount,omitempty"`
+	MovieCharCountPercent    float64   `json:"movieCharCountPercent,omitempty"`
+	MovieCharRank            int       `json:"movieCharRank,omitempty"`
+	MovieCharContexts        int       `json:"movieCharContexts,omitempty"`
+	MovieCharContextsPercent float64   `json:"movieCharContextsPercent,omitempty"`
+	BookCharCount            int       `json:"bookCharCount,omitempty"`
+	BookCharCountPercent     float64   `json:"bookCharCountPercent,omitempty"`
+	BookCharRank             int       `json:"bookCharRank,omitempty"`
+	MovieWordCount           int       `json:"movieWordCount,omitempty"`
+	MovieWordCountPercent    float64   `json:"movieWordCountPercent,omitempty"`
+	MovieWordRank            int       `json:"movieWordRank,omitempty"`
+	MovieWordContexts        int       `json:"movieWordContexts,omitempty"`
+	MovieWordContextsPercent float64   `json:"movieWordContextsPercent,omitempty"`
+	BookWordCount            int       `json:"bookWordCount,omitempty"`
+	BookWordCountPercent     float64   `json:"bookWordCountPercent,omitempty"`
+	BookWordRank             int       `json:"bookWordRank,omitempty"`
+}
+
+// TopWord is a common word containing a character
+type TopWord struct {
+	Word  string  `json:"word"`
+	Trad  string  `json:"trad,omitempty"`
+	Share float64 `json:"share,omitempty"` // Share of the character's uses in this word
+	Gloss string  `json:"gloss,omitempty"`
+}
+
+// Strings is a list of strings that also decodes from a single string, as some
+// fields of the dump are written both ways
+type Strings []string
+
+// UnmarshalJSON decodes a string or an array of strings
+func (s *Strings) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*s = Strings{one}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// Etymology explains how a character was formed
+type Etymology struct {
+	Type     string `json:"type"`               // pictographic, ideographic or pictophonetic
+	Hint     string `json:"hint,omitempty"`     // Short explanation, such as "water"
+	Semantic string `json:"semantic,omitempty"` // Meaning component of a pictophonetic character
+	Phonetic string `json:"phonetic,omitempty"` // Sound component of a pictophonetic character
 }
 
 // GetID returns the entry ID
@@ -19,6 +19,49 @@ func (i *Importer) Name() string {
 	return "chinese_words"
 }
 
+// rawWord is a word as written in the dong-chinese dump (JSONL) or the older JSON
+// array format, which names some fields differently
+type rawWord struct {
+	ID          string             `json:"_id"`
+	Trad        string             `json:"trad"`        // JSONL
+	Word        string             `json:"word"`        // JSON
+	Simp        string             `json:"simp"`        // JSONL
+	Simplified  string             `json:"simplified"`  // JSON
+	Items       []Item             `json:"items"`       // JSONL
+	Pinyin      stringList         `json:"pinyin"`      // JSON
+	Definitions stringList         `json:"definitions"` // JSON
+	Gloss       string             `json:"gloss"`
+	Statistics  *rawStatistics     `json:"statistics"` // JSONL
+	HSK         float64            `json:"hsk"`        // JSON
+	Frequency   map[string]float64 `json:"frequency"`
+}
+
+// rawStatistics holds the statistics of a JSONL word that are imported
+type rawStatistics struct {
+	HskLevel float64 `json:"hskLevel"`
+}
+
+// stringList is a list of strings that also decodes from a single string
+type stringList []string
+
+// UnmarshalJSON decodes a string or an array of strings
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*l = stringList{one}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
 // Import reads and processes the Chinese word dictionary
 func (i *Importer) Import(path string) ([]common.Entry, error) {
 	file, err := os.Open(path)
@@ -33,13 +76,12 @@ func (i *Importer) Import(path string) ([]common.Entry, error) {
 	fmt.Printf("DEBUG: Importing Chinese word dictionary from %s (isJSONL: %v)\n", path, isJSONL)
 
 	// Parse the file based on its format
-	var rawEntries []map[string]interface{}
+	var rawEntries []rawWord
 
 	if isJSONL {
 		// Parse JSONL (one JSON object per line)
 		scanner := bufio.NewScanner(file)
 		lineCount := 0
-		ribenFound := false
 
 		// Set a larger buffer size for the scanner
 		const maxScanTokenSize = 1024 * 1024 // 1MB
@@ -51,28 +93,20 @@ func (i *Importer) Import(path string) ([]common.Entry, error) {
 			line := scanner.Text()
 
 			// Debug: Check for "日本" in the raw line
+			ribenFound := false
 			if strings.Contains(line, "日本") && !strings.Contains(line, "日本國誌") && !strings.Contains(line, "日本国志") {
 				fmt.Printf("DEBUG: Found '日本' in line %d: %s\n", lineCount, line[:100]+"...")
 				ribenFound = true
 			}
 
-			var entry map[string]interface{}
+			var entry rawWord
 			if err := json.Unmarshal([]byte(line), &entry); err != nil {
 				return nil, fmt.Errorf("error parsing JSONL line %d: %v", lineCount, err)
 			}
 
 			// Debug: Check for "日本" in the parsed entry
-			if ribenFound {
-				if word, ok := entry["simp"]; ok {
-					if wordStr, ok := word.(string); ok && wordStr == "日本" {
-						fmt.Printf("DEBUG: Found entry with simp='日本': %+v\n", entry)
-					}
-				}
-				if word, ok := entry["trad"]; ok {
-					if wordStr, ok := word.(string); ok && wordStr == "日本" {
-						fmt.Printf("DEBUG: Found entry with trad='日本': %+v\n", entry)
-					}
-				}
+			if ribenFound && (entry.Simp == "日本" || entry.Trad == "日本") {
+				fmt.Printf("DEBUG: Found entry with simp/trad='日本': %+v\n", entry)
 			}
 
 			rawEntries = append(rawEntries, entry)
@@ -91,167 +125,55 @@ func (i *Importer) Import(path string) ([]common.Entry, error) {
 		fmt.Printf("DEBUG: Processed JSON array with %d entries\n", len(rawEntries))
 	}
 
-	// Debug: Count entries containing "日本"
-	var ribenEntries []map[string]interface{}
-	for _, entry := range rawEntries {
-		// Check all possible field names for "日本"
-		fieldsToCheck := []string{"word", "simplified", "simp", "trad", "traditional"}
-
-		for _, field := range fieldsToCheck {
-			if word, ok := entry[field]; ok {
-				if wordStr, ok := word.(string); ok && wordStr == "日本" {
-					// Only add if not already added
-					alreadyAdded := false
-					for _, e := range ribenEntries {
-						// Compare by _id since maps can't be directly compared
-						if eID, ok := e["_id"]; ok {
-							if entryID, ok := entry["_id"]; ok && eID == entryID {
-								alreadyAdded = true
-								break
-							}
-						}
-					}
-
-					if !alreadyAdded {
-						ribenEntries = append(ribenEntries, entry)
-					}
-				}
-			}
-		}
-	}
-
 	// Create a slice of entries for sorting
 	tempEntries := make([]ChineseWordEntry, len(rawEntries))
-	for i, rawEntry := range rawEntries {
-		entry := ChineseWordEntry{}
-
-		// Map _id to ID (we'll replace this with sequential IDs later)
-		if id, ok := rawEntry["_id"]; ok {
-			if idStr, ok := id.(string); ok {
-				entry.ID = idStr
-			}
-		}
-
-		// Map traditional form
-		// First try "trad" (JSONL format)
-		if trad, ok := rawEntry["trad"]; ok {
-			if tradStr, ok := trad.(string); ok {
-				entry.Traditional = tradStr
-				// If no simplified form is specified, use the traditional form
-				entry.Simplified = tradStr
-			}
-		} else if word, ok := rawEntry["word"]; ok {
-			// Then try "word" (JSON format)
-			if wordStr, ok := word.(string); ok {
-				entry.Traditional = wordStr
-				// If no simplified form is specified, use the traditional form
-				entry.Simplified = wordStr
-			}
+	for i, raw := range rawEntries {
+		// The ID is replaced with a sequential ID below
+		entry := ChineseWordEntry{ID: raw.ID}
+
+		// Map traditional form: "trad" (JSONL format), then "word" (JSON format).
+		// If no simplified form is specified, use the traditional form.
+		entry.Traditional = raw.Trad
+		if entry.Traditional == "" {
+			entry.Traditional = raw.Word
 		}
+		entry.Simplified = entry.Traditional
 
-		// Map simplified form
-		// First try "simp" (JSONL format)
-		if simp, ok := rawEntry["simp"]; ok {
-			if simpStr, ok := simp.(string); ok {
-				entry.Simplified = simpStr
-			}
-		} else if simp, ok := rawEntry["simplified"]; ok {
-			// Then try "simplified" (JSON format)
-			if simpStr, ok := simp.(string); ok {
-				entry.Simplified = simpStr
-			}
+		// Map simplified form: "simp" (JSONL format), then "simplified" (JSON format)
+		if raw.Simp != "" {
+			entry.Simplified = raw.Simp
+		} else if raw.Simplified != "" {
+			entry.Simplified = raw.Simplified
 		}
 
-		// Map pinyin
-		// First try JSONL format (items array with pinyin field)
-		if items, ok := rawEntry["items"]; ok {
-			if itemsArr, ok := items.([]interface{}); ok && len(itemsArr) > 0 {
-				for _, item := range itemsArr {
-					if itemMap, ok := item.(map[string]interface{}); ok {
-						if pinyin, ok := itemMap["pinyin"]; ok {
-							if pinyinStr, ok := pinyin.(string); ok {
-								entry.Pinyin = append(entry.Pinyin, pinyinStr)
-							}
-						}
-
-						// Extract definitions from items
-						if defs, ok := itemMap["definitions"]; ok {
-							if defsArr, ok := defs.([]interface{}); ok {
-								for _, def := range defsArr {
-									if defStr, ok := def.(string); ok {
-										entry.Definitions = append(entry.Definitions, defStr)
-									}
-								}
-							}
-						}
-					}
-				}
-			}
+		// Map readings: JSONL entries have items, one reading per item, projected
+		// onto the flat lists older clients read; JSON entries have flat lists
+		if raw.Items != nil {
+			entry.Items = raw.Items
+			entry.Pinyin = entry.Readings()
+			entry.Definitions = entry.ItemDefinitions()
 		} else {
-			// Try JSON format
-			if pinyin, ok := rawEntry["pinyin"]; ok {
-				if pinyinArr, ok := pinyin.([]interface{}); ok {
-					entry.Pinyin = make([]string, len(pinyinArr))
-					for j, p := range pinyinArr {
-						if pStr, ok := p.(string); ok {
-							entry.Pinyin[j] = pStr
-						}
-					}
-				} else if pinyinStr, ok := pinyin.(string); ok {
-					entry.Pinyin = []string{pinyinStr}
-				}
-			}
-
-			// Map definitions (JSON format)
-			if defs, ok := rawEntry["definitions"]; ok {
-				if defsArr, ok := defs.([]interface{}); ok {
-					entry.Definitions = make([]string, len(defsArr))
-					for j, d := range defsArr {
-						if dStr, ok := d.(string); ok {
-							entry.Definitions[j] = dStr
-						}
-					}
-				} else if defStr, ok := defs.(string); ok {
-					entry.Definitions = []string{defStr}
-				}
-			}
+			entry.Pinyin = raw.Pinyin
+			entry.Definitions = raw.Definitions
 		}
 
-		// If no definitions found yet, try the gloss field (JSONL format)
-		if len(entry.Definitions) == 0 {
-			if gloss, ok := rawEntry["gloss"]; ok {
-				if glossStr, ok := gloss.(string); ok {
-					entry.Definitions = []string{glossStr}
-				}
-			}
+		// If no definitions found yet, use the gloss field (JSONL format)
+		if len(entry.Definitions) == 0 && raw.Gloss != "" {
+			entry.Definitions = []string{raw.Gloss}
 		}
 
-		// Map HSK level
-		// First try statistics.hskLevel (JSONL format)
-		if stats, ok := rawEntry["statistics"]; ok {
-			if statsMap, ok := stats.(map[string]interface{}); ok {
-				if hskLevel, ok := statsMap["hskLevel"]; ok {
-					if hskFloat, ok := hskLevel.(float64); ok {
-						entry.HskLevel = int(hskFloat)
-					}
-				}
-			}
-		} else if hsk, ok := rawEntry["hsk"]; ok {
-			// Then try hsk (JSON format)
-			if hskFloat, ok := hsk.(float64); ok {
-				entry.HskLevel = int(hskFloat)
-			}
+		// Map HSK level: statistics.hskLevel (JSONL format), then hsk (JSON format)
+		if raw.Statistics != nil {
+			entry.HskLevel = int(raw.Statistics.HskLevel)
+		} else {
+			entry.HskLevel = int(raw.HSK)
 		}
 
 		// Map frequency
-		if freq, ok := rawEntry["frequency"]; ok {
-			if freqMap, ok := freq.(map[string]interface{}); ok {
-				entry.Frequency = make(map[string]int)
-				for k, v := range freqMap {
-					if vFloat, ok := v.(float64); ok {
-						entry.Frequency[k] = int(vFloat)
-					}
-				}
+		if raw.Frequency != nil {
+			entry.Frequency = make(map[string]int, len(raw.Frequency))
+			for k, v := range raw.Frequency {
+				entry.Frequency[k] = int(v)
 			}
 		}
 
@@ -0,0 +1,90 @@
+package chinese_words
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testDump is an abridged dong-chinese word dump with a word read two ways
+const testDump = `{"_id":"a1","simp":"行","trad":"行","items":[{"source":"cedict","pinyin":"xíng","definitions":["to walk","to go"]},{"source":"cedict","pinyin":"háng","definitions":["row","line"],"classifiers":["排"]},{"source":"unicode","pinyin":"xíng","tang":["*ɦɣæŋ"]}],"statistics":{"hskLevel":1}}
+{"_id":"a2","simp":"发","trad":"發","items":[{"source":"cedict","pinyin":"fā","simpTrad":"trad","definitions":["to send out"]},{"source":"unicode","pinyin":"fā","definitions":["to send out"]}]}
+{"_id":"a3","simp":"们","trad":"們","items":[{"source":"unicode","pinyin":"men"}],"gloss":"plural marker"}
+`
+
+func TestImportItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dictionary_word_test.jsonl")
+	if err := os.WriteFile(path, []byte(testDump), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := (&Importer{}).Import(path)
+	if err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+
+	// Entries are sorted by traditional form: 們 U+5011, 發 U+767C, 行 U+884C
+	walk := entries[2].(ChineseWordEntry)
+	if len(walk.Items) != 3 || walk.HskLevel != 1 {
+		t.Fatalf("Expected 3 items for 行, got %+v", walk)
+	}
+	row := walk.Items[1]
+	if row.Source != "cedict" || row.Pinyin != "háng" || !reflect.DeepEqual(row.Definitions, []string{"row", "line"}) || !reflect.DeepEqual(row.Classifiers, []string{"排"}) {
+		t.Errorf("Unexpected item: %+v", row)
+	}
+	if !reflect.DeepEqual(walk.Items[2].Tang, []string{"*ɦɣæŋ"}) {
+		t.Errorf("Expected the Tang reading, got %+v", walk.Items[2])
+	}
+
+	// The legacy lists hold each reading and definition of the items once
+	if !reflect.DeepEqual(walk.Pinyin, []string{"xíng", "háng"}) {
+		t.Errorf("Expected legacy pinyin, got %v", walk.Pinyin)
+	}
+	if !reflect.DeepEqual(walk.Definitions, []string{"to walk", "to go", "row", "line"}) {
+		t.Errorf("Expected legacy definitions, got %v", walk.Definitions)
+	}
+
+	send := entries[1].(ChineseWordEntry)
+	if send.Traditional != "發" || send.Simplified != "发" || send.Items[0].SimpTrad != "trad" || !reflect.DeepEqual(send.Definitions, []string{"to send out"}) {
+		t.Errorf("Unexpected entry for 發: %+v", send)
+	}
+
+	// Items without definitions fall back to the gloss
+	if plural := entries[0].(ChineseWordEntry); !reflect.DeepEqual(plural.Definitions, []string{"plural marker"}) {
+		t.Errorf("Expected the gloss for 們, got %v", plural.Definitions)
+	}
+}
+
+func TestImportArray(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dictionary_word_test.json")
+	dump := `[{"word":"銀行","simplified":"银行","pinyin":"yínháng","definitions":["bank"],"hsk":2,"frequency":{"all":42}}]`
+	if err := os.WriteFile(path, []byte(dump), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := (&Importer{}).Import(path)
+	if err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+	bank := entries[0].(ChineseWordEntry)
+	if bank.Traditional != "銀行" || bank.Simplified != "银行" || bank.HskLevel != 2 || bank.Frequency["all"] != 42 {
+		t.Errorf("Unexpected entry: %+v", bank)
+	}
+	if !reflect.DeepEqual(bank.Pinyin, []string{"yínháng"}) || !reflect.DeepEqual(bank.Definitions, []string{"bank"}) {
+		t.Errorf("Expected flat lists, got %v %v", bank.Pinyin, bank.Definitions)
+	}
+}
+
+func TestReadings(t *testing.T) {
+	w := ChineseWordEntry{
+		Items:  []Item{{Pinyin: "xíng"}, {Pinyin: ""}, {Pinyin: "háng"}, {Pinyin: "xíng"}},
+		Pinyin: []string{"háng", "hàng"},
+	}
+	if got := w.Readings(); !reflect.DeepEqual(got, []string{"xíng", "háng", "hàng"}) {
+		t.Errorf("Readings() = %v", got)
+	}
+}
@@ -1,14 +1,72 @@
 package chinese_words
 
+import "kiokun-go/dictionaries/common"
+
 // ChineseWordEntry represents a single Chinese word entry
 type ChineseWordEntry struct {
-	ID          string         `json:"id"`
-	Traditional string         `json:"traditional"`
-	Simplified  string         `json:"simplified"`
-	Pinyin      []string       `json:"pinyin,omitempty"`
-	Definitions []string       `json:"definitions,omitempty"`
-	HskLevel    int            `json:"hskLevel,omitempty"`
-	Frequency   map[string]int `json:"frequency,omitempty"`
+	ID          string           `json:"id"`
+	Traditional string           `json:"traditional"`
+	Simplified  string           `json:"simplified"`
+	Items       []Item           `json:"items,omitempty"`       // Readings with their own definitions, by source
+	Pinyin      []string         `json:"pinyin,omitempty"`      // Every reading of Items, for older clients
+	Definitions []string         `json:"definitions,omitempty"` // Every definition of Items, for older clients
+	HskLevel    int              `json:"hskLevel,omitempty"`
+	Frequency   map[string]int   `json:"frequency,omitempty"`
+	Cognates    []common.Cognate `json:"cognates,omitempty"` // JMdict words with a matching kanji form
+}
+
+// Item is one reading of a word in one source, with the definitions that belong to it
+type Item struct {
+	Source      string   `json:"source,omitempty"`      // Source dictionary, such as cedict or unicode
+	Pinyin      string   `json:"pinyin,omitempty"`      // Reading with tone marks
+	SimpTrad    string   `json:"simpTrad,omitempty"`    // simp or trad when the item only applies to that form
+	Definitions []string `json:"definitions,omitempty"` // Definitions for this reading
+	Classifiers []string `json:"classifiers,omitempty"` // Measure words used with the word in this sense
+	Tang        []string `json:"tang,omitempty"`        // Tang dynasty readings
+	Refs        []Ref    `json:"refs,omitempty"`        // Words the definitions refer to
+}
+
+// Ref is a word a definition refers to, such as the 個 of "variant of 個|个[ge4]"
+type Ref struct {
+	Type        string `json:"type"` // The phrase before the word: variant, old variant, see, see also, ...
+	Traditional string `json:"traditional"`
+	Simplified  string `json:"simplified,omitempty"`
+	Pinyin      string `json:"pinyin,omitempty"`
+}
+
+// Readings returns every pinyin reading of the word, those of its items first, each
+// once
+func (w ChineseWordEntry) Readings() []string {
+	var readings []string
+	seen := make(map[string]bool)
+	add := func(reading string) {
+		if reading != "" && !seen[reading] {
+			seen[reading] = true
+			readings = append(readings, reading)
+		}
+	}
+	for _, item := range w.Items {
+		add(item.Pinyin)
+	}
+	for _, reading := range w.Pinyin {
+		add(reading)
+	}
+	return readings
+}
+
+// ItemDefinitions returns every definition of the word's items, each once
+func (w ChineseWordEntry) ItemDefinitions() []string {
+	var defs []string
+	seen := make(map[string]bool)
+	for _, item := range w.Items {
+		for _, def := range item.Definitions {
+			if !seen[def] {
+				seen[def] = true
+				defs = append(defs, def)
+			}
+		}
+	}
+	return defs
 }
 
 // GetID returns the entry ID
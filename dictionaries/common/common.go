@@ -15,6 +15,14 @@ type Entry interface {
 	GetFilename() string
 }
 
+// Cognate links an entry to a word in another language's dictionary that is
+// written the same way, such as Japanese 電話 and Chinese 電話/电话
+type Cognate struct {
+	ID      int64  `json:"i"`           // Sharded ID of the other entry
+	Text    string `json:"t"`           // Other entry's matching form
+	Overlap bool   `json:"o,omitempty"` // Whether the glosses of the two entries overlap
+}
+
 // DictionaryImporter defines the interface for dictionary importers
 type DictionaryImporter interface {
 	Name() string
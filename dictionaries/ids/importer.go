@@ -3,6 +3,7 @@ package ids
 import (
 	"bufio"
 	"os"
+	"path/filepath"
 	"strings"
 	"unicode/utf8"
 
@@ -17,8 +18,31 @@ func (i *Importer) Name() string {
 	return "ids"
 }
 
-// Import reads and processes the IDS file
+// Import reads and processes the IDS file. A path with a glob pattern, such as
+// IDS-UCS-Ext-B*.txt for the split CHISE Extension B files, imports every matching
+// file and no entries when none match.
 func (i *Importer) Import(path string) ([]common.Entry, error) {
+	if !strings.ContainsAny(filepath.Base(path), "*?") {
+		return i.importFile(path)
+	}
+
+	paths, err := filepath.Glob(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []common.Entry
+	for _, p := range paths {
+		fileEntries, err := i.importFile(p)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
+
+// importFile reads one IDS file, in either the CHISE or the cjkvi-ids format
+func (i *Importer) importFile(path string) ([]common.Entry, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -34,7 +58,7 @@ func (i *Importer) Import(path string) ([]common.Entry, error) {
 		line := scanner.Text()
 
 		// Skip comments and empty lines
-		if strings.HasPrefix(line, ";;") || strings.TrimSpace(line) == "" {
+		if strings.HasPrefix(line, ";;") || strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
 			continue
 		}
 
@@ -46,27 +70,37 @@ func (i *Importer) Import(path string) ([]common.Entry, error) {
 
 		codepoint := fields[0]
 		character := fields[1]
-		ids := fields[2]
 
 		// Check if the character field contains exactly one character
 		if utf8.RuneCountInString(character) != 1 {
 			continue
 		}
 
-		// Check for apparent IDS
+		// Collect every decomposition, and the apparent IDS if there is one
 		apparentIDS := ""
-		for i := 3; i < len(fields); i++ {
-			if strings.HasPrefix(fields[i], "@apparent=") {
-				apparentIDS = strings.TrimPrefix(fields[i], "@apparent=")
-				break
+		var variants []Variant
+		for _, field := range fields[2:] {
+			switch {
+			case strings.HasPrefix(field, "@apparent="):
+				if apparentIDS == "" {
+					apparentIDS = strings.TrimPrefix(field, "@apparent=")
+				}
+			case strings.HasPrefix(field, "@"), strings.HasPrefix(field, "*"), field == "":
+				// Other annotations and notes
+			default:
+				variants = append(variants, parseVariant(field))
 			}
 		}
+		if len(variants) == 0 {
+			continue
+		}
 
 		entry := IDSEntry{
 			ID:          codepoint,
 			Character:   character,
-			IDS:         ids,
+			IDS:         variants[0].IDS,
 			ApparentIDS: apparentIDS,
+			Variants:    variants,
 		}
 
 		entries = append(entries, entry)
@@ -78,3 +112,27 @@ func (i *Importer) Import(path string) ([]common.Entry, error) {
 
 	return entries, nil
 }
+
+// parseVariant splits a decomposition field into its sequence and source regions,
+// written as ⿰氵每[GTJ] by cjkvi-ids or ^⿰氵每$(GTJ) by BabelStone, and parses it.
+// Sequences that cannot be parsed, usually for unencoded components, keep a nil Tree.
+func parseVariant(field string) Variant {
+	v := Variant{IDS: field}
+	switch {
+	case strings.HasPrefix(field, "^"):
+		if end := strings.Index(field, "$"); end > 0 {
+			v.IDS = field[1:end]
+			v.Regions = strings.Trim(field[end+1:], "()")
+		}
+	case strings.HasSuffix(field, "]"):
+		if start := strings.LastIndex(field, "["); start > 0 {
+			v.IDS = field[:start]
+			v.Regions = field[start+1 : len(field)-1]
+		}
+	}
+
+	if tree, err := Parse(v.IDS); err == nil {
+		v.Tree = tree
+	}
+	return v
+}
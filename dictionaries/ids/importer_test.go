@@ -75,3 +75,88 @@ U+4E04	丄	⿱一一	@apparent=⿱一丨
 		}
 	}
 }
+
+func TestImporter_ImportRegionalVariants(t *testing.T) {
+	tempDir := t.TempDir()
+	testFilePath := filepath.Join(tempDir, "ids.txt")
+
+	// cjkvi-ids lines tag each decomposition with its source regions
+	testData := `#	{"license": "GPLv2"}
+U+4E00	一	一
+U+4E0E	与	⿹⿺一㇉一[GTKV]	⿺⿹一㇉一[J]
+U+5203	刃	⿻刀丶[GTKV]	⿻刀㇒[J]
+U+9AA8	骨	^⿱⿵冂⿰㇆丨⿵冖月$(G)	^⿱⿵冂⿰丨㇆⿵冖月$(TJKV)
+U+5350	卐	⿻⿱?	
+`
+	if err := os.WriteFile(testFilePath, []byte(testData), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	entries, err := (&Importer{}).Import(testFilePath)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 entries, got %d", len(entries))
+	}
+
+	testCases := []struct {
+		index    int
+		regions  []string
+		expected string
+	}{
+		{0, []string{"J"}, "一"},
+		{1, []string{"J"}, "⿺⿹一㇉一"},
+		{1, []string{"G", "T"}, "⿹⿺一㇉一"},
+		{2, []string{"J"}, "⿻刀㇒"},
+		{2, []string{"T"}, "⿻刀丶"},
+		{3, []string{"G", "T"}, "⿱⿵冂⿰㇆丨⿵冖月"},
+		{3, []string{"J"}, "⿱⿵冂⿰丨㇆⿵冖月"},
+	}
+	for _, tc := range testCases {
+		entry := entries[tc.index].(IDSEntry)
+		if got := entry.ForRegion(tc.regions...); got != tc.expected {
+			t.Errorf("%s for %v: expected %s, got %s", entry.Character, tc.regions, tc.expected, got)
+		}
+	}
+
+	entry := entries[1].(IDSEntry)
+	if entry.IDS != "⿹⿺一㇉一" || len(entry.Variants) != 2 || entry.Variants[1].Regions != "J" {
+		t.Errorf("Expected two tagged variants with the first as IDS, got %+v", entry)
+	}
+	if entry.Variants[0].Tree == nil || entry.Variants[0].Tree.Operator != SurroundFromUpperRight {
+		t.Errorf("Expected a parsed ⿹ tree, got %+v", entry.Variants[0].Tree)
+	}
+
+	// Malformed sequences are kept without a tree
+	if broken := entries[4].(IDSEntry); broken.IDS != "⿻⿱?" || broken.Variants[0].Tree != nil {
+		t.Errorf("Expected the malformed IDS to be kept unparsed, got %+v", broken)
+	}
+}
+
+func TestImporter_ImportGlob(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"IDS-UCS-Ext-B-1.txt": "U+20000	𠀀	⿱一𠃊\n",
+		"IDS-UCS-Ext-B-2.txt": "U+21000	𡀀	⿰口⿱爫𡕒\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	entries, err := (&Importer{}).Import(filepath.Join(tempDir, "IDS-UCS-Ext-B*.txt"))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 entries from the split files, got %d", len(entries))
+	}
+
+	// A missing extension imports nothing rather than failing
+	entries, err = (&Importer{}).Import(filepath.Join(tempDir, "IDS-UCS-Ext-I*.txt"))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries and no error for a missing extension, got %d and %v", len(entries), err)
+	}
+}
@@ -1,6 +1,8 @@
 package ids
 
 import (
+	"strings"
+
 	"kiokun-go/dictionaries/common"
 )
 
@@ -11,5 +13,19 @@ func init() {
 	// Register the Extension A IDS file as a separate dictionary
 	common.RegisterDictionary("ids_ext_a", "IDS-UCS-Ext-A.txt", &Importer{})
 
-	// Additional extension files can be registered here as needed
+	// Register Extensions B through I. CHISE splits Extension B into several files,
+	// so the file names are glob patterns, and a missing extension imports no entries.
+	common.RegisterDictionary("ids_ext_b", "IDS-UCS-Ext-B*.txt", &Importer{})
+	common.RegisterDictionary("ids_ext_c", "IDS-UCS-Ext-C*.txt", &Importer{})
+	common.RegisterDictionary("ids_ext_d", "IDS-UCS-Ext-D*.txt", &Importer{})
+	common.RegisterDictionary("ids_ext_e", "IDS-UCS-Ext-E*.txt", &Importer{})
+	common.RegisterDictionary("ids_ext_f", "IDS-UCS-Ext-F*.txt", &Importer{})
+	common.RegisterDictionary("ids_ext_g", "IDS-UCS-Ext-G*.txt", &Importer{})
+	common.RegisterDictionary("ids_ext_h", "IDS-UCS-Ext-H*.txt", &Importer{})
+	common.RegisterDictionary("ids_ext_i", "IDS-UCS-Ext-I*.txt", &Importer{})
+}
+
+// IsIDSDictionary reports whether a registered dictionary name is one of the IDS files
+func IsIDSDictionary(name string) bool {
+	return name == "ids" || strings.HasPrefix(name, "ids_ext_")
 }
@@ -0,0 +1,169 @@
+package ids
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Operator is an ideographic description character, describing how the
+// components of a character are arranged
+type Operator rune
+
+const (
+	LeftToRight            Operator = '⿰' // U+2FF0
+	AboveToBelow           Operator = '⿱' // U+2FF1
+	LeftToMiddleAndRight   Operator = '⿲' // U+2FF2
+	AboveToMiddleAndBelow  Operator = '⿳' // U+2FF3
+	FullSurround           Operator = '⿴' // U+2FF4
+	SurroundFromAbove      Operator = '⿵' // U+2FF5
+	SurroundFromBelow      Operator = '⿶' // U+2FF6
+	SurroundFromLeft       Operator = '⿷' // U+2FF7
+	SurroundFromUpperLeft  Operator = '⿸' // U+2FF8
+	SurroundFromUpperRight Operator = '⿹' // U+2FF9
+	SurroundFromLowerLeft  Operator = '⿺' // U+2FFA
+	Overlaid               Operator = '⿻' // U+2FFB
+	SurroundFromRight      Operator = '⿼' // U+2FFC, Unicode 15.1
+	SurroundFromLowerRight Operator = '⿽' // U+2FFD, Unicode 15.1
+	HorizontalReflection   Operator = '⿾' // U+2FFE, Unicode 15.1
+	Rotation               Operator = '⿿' // U+2FFF, Unicode 15.1
+	Subtraction            Operator = '㇯' // U+31EF, Unicode 15.1
+)
+
+// Arity returns the number of components an operator takes, or 0 if the rune is
+// not an operator
+func (o Operator) Arity() int {
+	switch o {
+	case HorizontalReflection, Rotation:
+		return 1
+	case LeftToMiddleAndRight, AboveToMiddleAndBelow:
+		return 3
+	case LeftToRight, AboveToBelow, FullSurround, SurroundFromAbove, SurroundFromBelow,
+		SurroundFromLeft, SurroundFromUpperLeft, SurroundFromUpperRight, SurroundFromLowerLeft,
+		Overlaid, SurroundFromRight, SurroundFromLowerRight, Subtraction:
+		return 2
+	}
+	return 0
+}
+
+// Node is a parsed Ideographic Description Sequence: either a component, or an
+// operator applied to its child sequences
+type Node struct {
+	Operator  Operator `json:"o,omitempty"` // Zero for a component
+	Component string   `json:"c,omitempty"` // A character, or an entity reference such as &CDP-8958;
+	Children  []*Node  `json:"n,omitempty"` // Operator.Arity() children
+}
+
+// Parse parses an Ideographic Description Sequence such as ⿰氵⿱𠂉母 into a tree,
+// checking that every operator has as many components as its arity. CHISE entity
+// references that stand for an operator variant, such as &U-i001+2FF1;, are read as
+// that operator.
+func Parse(sequence string) (*Node, error) {
+	p := &parser{rest: sequence}
+	node, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing IDS %q: %v", sequence, err)
+	}
+	if p.rest != "" {
+		return nil, fmt.Errorf("error parsing IDS %q: unexpected %q after the sequence", sequence, p.rest)
+	}
+	return node, nil
+}
+
+// parser reads an IDS one token at a time
+type parser struct {
+	rest string
+}
+
+// parse reads one node and its children
+func (p *parser) parse() (*Node, error) {
+	token, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("sequence ended before a component")
+	}
+
+	op := tokenOperator(token)
+	if op == 0 {
+		return &Node{Component: token}, nil
+	}
+
+	node := &Node{Operator: op, Children: make([]*Node, 0, op.Arity())}
+	for i := 0; i < op.Arity(); i++ {
+		child, err := p.parse()
+		if err != nil {
+			return nil, fmt.Errorf("%c takes %d components: %v", op, op.Arity(), err)
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node, nil
+}
+
+// next returns the next character or entity reference
+func (p *parser) next() (string, bool) {
+	if p.rest == "" {
+		return "", false
+	}
+	if strings.HasPrefix(p.rest, "&") {
+		if end := strings.IndexByte(p.rest, ';'); end > 0 {
+			token := p.rest[:end+1]
+			p.rest = p.rest[end+1:]
+			return token, true
+		}
+	}
+	r := []rune(p.rest)[0]
+	p.rest = p.rest[len(string(r)):]
+	return string(r), true
+}
+
+// tokenOperator returns the operator a token stands for, or 0 for a component
+func tokenOperator(token string) Operator {
+	if strings.HasPrefix(token, "&") {
+		// Glyph variants of operators are written &U-i001+2FF1;
+		plus := strings.LastIndexByte(token, '+')
+		if !strings.HasPrefix(token, "&U-") || plus < 0 {
+			return 0
+		}
+		code, err := strconv.ParseInt(strings.TrimSuffix(token[plus+1:], ";"), 16, 32)
+		if err != nil || Operator(code).Arity() == 0 {
+			return 0
+		}
+		return Operator(code)
+	}
+
+	op := Operator([]rune(token)[0])
+	if op.Arity() == 0 {
+		return 0
+	}
+	return op
+}
+
+// String writes the tree back as an Ideographic Description Sequence
+func (n *Node) String() string {
+	var b strings.Builder
+	n.write(&b)
+	return b.String()
+}
+
+// write appends the sequence of a node and its children
+func (n *Node) write(b *strings.Builder) {
+	if n.Operator == 0 {
+		b.WriteString(n.Component)
+		return
+	}
+	b.WriteRune(rune(n.Operator))
+	for _, child := range n.Children {
+		child.write(b)
+	}
+}
+
+// Components returns the components at the leaves of the tree, in order
+func (n *Node) Components() []string {
+	if n.Operator == 0 {
+		return []string{n.Component}
+	}
+	var comps []string
+	for _, child := range n.Children {
+		comps = append(comps, child.Components()...)
+	}
+	return comps
+}
@@ -0,0 +1,62 @@
+package ids
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		ids        string
+		components []string
+		want       string // Sequence written back, if different
+	}{
+		{"一", []string{"一"}, ""},
+		{"⿰氵每", []string{"氵", "每"}, ""},
+		{"⿰氵⿱𠂉母", []string{"氵", "𠂉", "母"}, ""},
+		{"⿲彳⿱山王攵", []string{"彳", "山", "王", "攵"}, ""},
+		{"⿱&CDP-8958;一", []string{"&CDP-8958;", "一"}, ""},
+		{"&U-i001+2FF1;亣八", []string{"亣", "八"}, "⿱亣八"},
+		{"⿾朩", []string{"朩"}, ""},
+		{"㇯言口", []string{"言", "口"}, ""},
+		{"⿼叉丶", []string{"叉", "丶"}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.ids, func(t *testing.T) {
+			tree, err := Parse(tc.ids)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			if got := tree.Components(); !reflect.DeepEqual(got, tc.components) {
+				t.Errorf("Expected components %v, got %v", tc.components, got)
+			}
+			want := tc.want
+			if want == "" {
+				want = tc.ids
+			}
+			if got := tree.String(); got != want {
+				t.Errorf("Expected %s written back, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestParseArity(t *testing.T) {
+	tree, err := Parse("⿳亠口⿰丶丶")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if tree.Operator != AboveToMiddleAndBelow || len(tree.Children) != 3 {
+		t.Fatalf("Expected ⿳ with 3 children, got %c with %d", tree.Operator, len(tree.Children))
+	}
+	if child := tree.Children[2]; child.Operator != LeftToRight || len(child.Children) != 2 {
+		t.Errorf("Expected the last child to be ⿰ with 2 children, got %+v", child)
+	}
+
+	for _, invalid := range []string{"", "⿰氵", "⿲彳山", "⿱一一一", "⿾", "㇯言"} {
+		if _, err := Parse(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
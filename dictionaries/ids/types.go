@@ -1,11 +1,50 @@
 package ids
 
+import "strings"
+
 // IDSEntry represents a single IDS (Ideographic Description Sequence) entry
 type IDSEntry struct {
-	ID          string `json:"id"`          // Codepoint (e.g., U+4E00)
-	Character   string `json:"character"`   // The character itself
-	IDS         string `json:"ids"`         // Ideographic Description Sequence
-	ApparentIDS string `json:"apparentIds"` // Apparent IDS (optional)
+	ID          string    `json:"id"`                 // Codepoint (e.g., U+4E00)
+	Character   string    `json:"character"`          // The character itself
+	IDS         string    `json:"ids"`                // Ideographic Description Sequence, the first variant
+	ApparentIDS string    `json:"apparentIds"`        // Apparent IDS (optional)
+	Variants    []Variant `json:"variants,omitempty"` // Every decomposition, in file order
+}
+
+// Variant is one decomposition of a character, as used by some source regions
+type Variant struct {
+	IDS     string `json:"ids"`               // Ideographic Description Sequence
+	Regions string `json:"regions,omitempty"` // Source region letters such as GTJKV, empty for all regions
+	Tree    *Node  `json:"tree,omitempty"`    // Parsed sequence, nil if it could not be parsed
+}
+
+// ForRegion returns the decomposition used by the first of the given source regions
+// (G for China, T for Taiwan, J for Japan, K for Korea, V for Vietnam) that has one,
+// falling back to an untagged variant and then to the first variant
+func (e IDSEntry) ForRegion(regions ...string) string {
+	return e.VariantForRegion(regions...).IDS
+}
+
+// VariantForRegion returns the variant ForRegion picks, with its parsed tree
+func (e IDSEntry) VariantForRegion(regions ...string) Variant {
+	for _, region := range regions {
+		for _, v := range e.Variants {
+			if strings.Contains(v.Regions, region) {
+				return v
+			}
+		}
+	}
+	for _, v := range e.Variants {
+		if v.Regions == "" {
+			return v
+		}
+	}
+	for _, v := range e.Variants {
+		if v.IDS == e.IDS {
+			return v
+		}
+	}
+	return Variant{IDS: e.IDS}
 }
 
 // GetID returns the entry ID
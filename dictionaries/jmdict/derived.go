@@ -0,0 +1,158 @@
+package jmdict
+
+// Derived data attached to words at build time. These fields are not part of
+// the JMdict source file; the processor fills them in before writing entries.
+
+// Conjugation holds the precomputed inflections of a single verb or adjective form
+type Conjugation struct {
+	Form        string `json:"f"`             // Dictionary form being conjugated
+	Class       string `json:"p"`             // Part-of-speech class that drives the table
+	Polite      string `json:"pol,omitempty"` // 食べます
+	Negative    string `json:"neg,omitempty"` // 食べない
+	Past        string `json:"pst,omitempty"` // 食べた
+	Te          string `json:"te,omitempty"`  // 食べて
+	Potential   string `json:"pot,omitempty"` // 食べられる
+	Passive     string `json:"pas,omitempty"` // 食べられる
+	Causative   string `json:"cau,omitempty"` // 食べさせる
+	Volitional  string `json:"vol,omitempty"` // 食べよう
+	Imperative  string `json:"imp,omitempty"` // 食べろ
+	Conditional string `json:"cnd,omitempty"` // 食べれば
+}
+
+// Furigana is the per-character reading alignment of one kanji form with one of its kana readings
+type Furigana struct {
+	Text    string         `json:"t"` // Kanji form
+	Reading string         `json:"r"` // Kana reading
+	Parts   []FuriganaPart `json:"p"` // 日本 → 日[に] 本[ほん]
+}
+
+// FuriganaPart is a run of base text and the reading shown above it.
+// Ruby is empty for kana that needs no annotation.
+type FuriganaPart struct {
+	Base string `json:"b"`
+	Ruby string `json:"r,omitempty"`
+}
+
+// WrittenForm lists, for one written form (kanji or kana), the readings valid with it
+// and the senses that apply to each pairing
+type WrittenForm struct {
+	Text     string        `json:"t"`
+	Readings []FormReading `json:"r"`
+}
+
+// FormReading is one reading of a form with the indexes of its applicable senses
+type FormReading struct {
+	Text   string `json:"t"`
+	Senses []int  `json:"s"`
+}
+
+// AppliesTo reports whether a kana reading can be used with a kanji form.
+// A nil list or ["*"] means every form; an explicit empty list means none
+// (jmdict-simplified writes [] for readings marked nokanji).
+func (k KanaEntry) AppliesTo(kanji string) bool {
+	if k.AppliesToKanji == nil {
+		return true
+	}
+	return containsForm(k.AppliesToKanji, kanji)
+}
+
+// AppliesTo reports whether a sense applies to a kanji form and kana reading.
+// An empty kanji form means the reading is used on its own.
+func (s Sense) AppliesTo(kanji, kana string) bool {
+	if kanji != "" && len(s.AppliesToKanji) > 0 && !containsForm(s.AppliesToKanji, kanji) {
+		return false
+	}
+	return len(s.AppliesToKana) == 0 || containsForm(s.AppliesToKana, kana)
+}
+
+// containsForm reports whether a restriction list names a form or the "*" wildcard
+func containsForm(restriction []string, form string) bool {
+	for _, r := range restriction {
+		if r == "*" || r == form {
+			return true
+		}
+	}
+	return false
+}
+
+// FormMatrix resolves the word's restrictions into an explicit form → readings → senses
+// matrix: one WrittenForm per kanji form, then one per kana form. Kana forms list only
+// themselves as their reading.
+func (w Word) FormMatrix() []WrittenForm {
+	forms := make([]WrittenForm, 0, len(w.Kanji)+len(w.Kana))
+
+	senses := func(kanji, kana string) []int {
+		indexes := []int{}
+		for i, s := range w.Sense {
+			if s.AppliesTo(kanji, kana) {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes
+	}
+
+	for _, k := range w.Kanji {
+		form := WrittenForm{Text: k.Text, Readings: []FormReading{}}
+		for _, r := range w.Kana {
+			if r.AppliesTo(k.Text) {
+				form.Readings = append(form.Readings, FormReading{Text: r.Text, Senses: senses(k.Text, r.Text)})
+			}
+		}
+		forms = append(forms, form)
+	}
+
+	// A kana form takes the senses of its reading, except those restricted
+	// to kanji forms the reading cannot be used with
+	for _, r := range w.Kana {
+		indexes := []int{}
+		for i, s := range w.Sense {
+			if !s.AppliesTo("", r.Text) {
+				continue
+			}
+			usable := len(s.AppliesToKanji) == 0
+			for _, k := range w.Kanji {
+				if !usable && r.AppliesTo(k.Text) && s.AppliesTo(k.Text, r.Text) {
+					usable = true
+				}
+			}
+			if usable {
+				indexes = append(indexes, i)
+			}
+		}
+		forms = append(forms, WrittenForm{
+			Text:     r.Text,
+			Readings: []FormReading{{Text: r.Text, Senses: indexes}},
+		})
+	}
+
+	return forms
+}
+
+// SensesForForm returns the senses that apply to a written form through any of its readings,
+// in their original order. It returns nil if the word has no such form.
+func (w Word) SensesForForm(text string) []Sense {
+	forms := w.Forms
+	if forms == nil {
+		forms = w.FormMatrix()
+	}
+
+	for _, form := range forms {
+		if form.Text != text {
+			continue
+		}
+		applies := make(map[int]bool)
+		for _, r := range form.Readings {
+			for _, i := range r.Senses {
+				applies[i] = true
+			}
+		}
+		result := []Sense{}
+		for i, s := range w.Sense {
+			if applies[i] {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
@@ -10,6 +10,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+
+	"kiokun-go/dictionaries/common"
 )
 
 func UnmarshalJmdictTypes(data []byte) (JmdictTypes, error) {
@@ -38,6 +40,12 @@ type Word struct {
 	Kanji []KanjiEntry `json:"kanji"`
 	Kana  []KanaEntry  `json:"kana"`
 	Sense []Sense      `json:"sense"`
+
+	// Build-time derived data (see derived.go)
+	Conjugations []Conjugation    `json:"cj,omitempty"`
+	Furigana     []Furigana       `json:"fg,omitempty"`
+	Forms        []WrittenForm    `json:"fm,omitempty"`
+	Cognates     []common.Cognate `json:"cg,omitempty"` // Chinese words with a matching kanji form
 }
 
 type KanjiEntry struct {
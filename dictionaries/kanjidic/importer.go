@@ -3,6 +3,7 @@ package kanjidic
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"kiokun-go/dictionaries/common"
 )
@@ -26,6 +27,14 @@ func (i *Importer) Import(path string) ([]common.Entry, error) {
 		return nil, err
 	}
 
+	// Map codepoints to characters so variant references can be resolved
+	codepoints := make(map[string]string)
+	for _, char := range dict.Characters {
+		for _, cp := range char.Codepoints {
+			codepoints[cp.Type+":"+cp.Value] = char.Literal
+		}
+	}
+
 	// Convert each Character in the file to our simplified Kanji type
 	entries := make([]common.Entry, len(dict.Characters))
 	for i, char := range dict.Characters {
@@ -39,9 +48,12 @@ func (i *Importer) Import(path string) ([]common.Entry, error) {
 			Stroke:    0,
 		}
 
-		// Extract stroke count from the first element if available
-		if char.Misc.StrokeCounts != nil && len(char.Misc.StrokeCounts) > 0 {
+		// Extract stroke count from the first element if available; the others are miscounts
+		if len(char.Misc.StrokeCounts) > 0 {
 			kanji.Stroke = char.Misc.StrokeCounts[0]
+			if len(char.Misc.StrokeCounts) > 1 {
+				kanji.StrokeMiscounts = char.Misc.StrokeCounts[1:]
+			}
 		}
 
 		// Extract grade if available
@@ -59,30 +71,100 @@ func (i *Importer) Import(path string) ([]common.Entry, error) {
 			kanji.Frequency = *char.Misc.Frequency
 		}
 
+		kanji.RadicalNames = char.Misc.RadicalNames
+
+		// Resolve variants given as codepoints to characters, and keep them all as given
+		kanji.Variants = resolveVariants(char.Misc.Variants, codepoints)
+		for _, v := range char.Misc.Variants {
+			kanji.VariantRefs = append(kanji.VariantRefs, Ref{Type: v.Type, Value: v.Value})
+		}
+
+		// Extract encodings
+		for _, cp := range char.Codepoints {
+			if kanji.Codepoints == nil {
+				kanji.Codepoints = make(map[string]string)
+			}
+			kanji.Codepoints[cp.Type] = cp.Value
+		}
+
+		// Extract radical numbers
+		for _, rad := range char.Radicals {
+			switch rad.Type {
+			case "classical":
+				kanji.Radical = rad.Value
+			case "nelson_c":
+				kanji.NelsonRadical = rad.Value
+			}
+		}
+
+		// Extract dictionary references, keeping the first of each type
+		for _, ref := range char.DictionaryReferences {
+			if kanji.References == nil {
+				kanji.References = make(map[string]string)
+			}
+			if _, ok := kanji.References[ref.Type]; !ok {
+				kanji.References[ref.Type] = ref.Value
+			}
+			if ref.Morohashi != nil && kanji.Morohashi == nil {
+				kanji.Morohashi = &MorohashiRef{Volume: ref.Morohashi.Volume, Page: ref.Morohashi.Page}
+			}
+		}
+
+		// Extract query codes
+		for _, q := range char.QueryCodes {
+			code := QueryRef{Type: q.Type, Value: q.Value}
+			if q.SkipMisclassification != nil {
+				code.Misclass = *q.SkipMisclassification
+			}
+			kanji.QueryCodes = append(kanji.QueryCodes, code)
+		}
+
 		// Extract readings and meanings
 		if char.ReadingMeaning != nil {
 			for _, group := range char.ReadingMeaning.Groups {
-				// Extract on and kun readings
 				for _, reading := range group.Readings {
-					if reading.Type == "ja_on" {
+					switch reading.Type {
+					case "ja_on":
 						kanji.OnYomi = append(kanji.OnYomi, reading.Value)
-					} else if reading.Type == "ja_kun" {
+					case "ja_kun":
 						kanji.KunYomi = append(kanji.KunYomi, reading.Value)
+					case "pinyin":
+						kanji.Pinyin = append(kanji.Pinyin, reading.Value)
+					case "korean_r":
+						kanji.KoreanRomanized = append(kanji.KoreanRomanized, reading.Value)
+					case "korean_h":
+						kanji.KoreanHangul = append(kanji.KoreanHangul, reading.Value)
+					case "vietnam":
+						kanji.Vietnamese = append(kanji.Vietnamese, reading.Value)
+					}
+
+					if reading.OnType != nil || reading.Status != nil {
+						note := ReadingNote{Reading: reading.Value}
+						if reading.OnType != nil {
+							note.OnType = *reading.OnType
+						}
+						if reading.Status != nil {
+							note.Status = *reading.Status
+						}
+						kanji.ReadingNotes = append(kanji.ReadingNotes, note)
 					}
 				}
 
-				// Extract English meanings
+				// English meanings go in Meanings, the others by language
 				for _, meaning := range group.Meanings {
 					if meaning.Lang == "en" {
 						kanji.Meanings = append(kanji.Meanings, meaning.Value)
+						continue
+					}
+					if kanji.OtherMeanings == nil {
+						kanji.OtherMeanings = make(map[string][]string)
 					}
+					kanji.OtherMeanings[meaning.Lang] = append(kanji.OtherMeanings[meaning.Lang], meaning.Value)
 				}
 			}
 
-			// Add nanori readings (name readings) to a separate field if needed
-			if len(char.ReadingMeaning.Nanori) > 0 {
-				kanji.Radicals = char.ReadingMeaning.Nanori // Reusing radicals field for nanori readings
-			}
+			// Nanori readings (name readings)
+			kanji.Nanori = char.ReadingMeaning.Nanori
 		}
 
 		entries[i] = kanji
@@ -90,3 +172,26 @@ func (i *Importer) Import(path string) ([]common.Entry, error) {
 
 	return entries, nil
 }
+
+// resolveVariants turns Kanjidic variant references into characters. UCS variants
+// are code points; JIS variants are looked up among the file's own codepoints.
+// Variants given as dictionary indexes (nelson_c, s_h, ...) cannot be resolved
+// and are skipped.
+func resolveVariants(variants []Variant, codepoints map[string]string) []string {
+	var result []string
+	for _, v := range variants {
+		var char string
+		switch v.Type {
+		case "ucs":
+			if cp, err := strconv.ParseInt(v.Value, 16, 32); err == nil {
+				char = string(rune(cp))
+			}
+		case "jis208", "jis212", "jis213":
+			char = codepoints[v.Type+":"+v.Value]
+		}
+		if char != "" {
+			result = append(result, char)
+		}
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package kanjidic
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testKanjidic is an abridged Kanjidic2 entry for 日 in the jmdict-simplified format
+const testKanjidic = `{
+  "version": "3.5.0",
+  "languages": ["en", "fr"],
+  "characters": [
+    {
+      "literal": "日",
+      "codepoints": [{"type": "jis208", "value": "1-38-92"}, {"type": "ucs", "value": "65e5"}],
+      "radicals": [{"type": "classical", "value": 72}, {"type": "nelson_c", "value": 73}],
+      "misc": {
+        "grade": 1,
+        "strokeCounts": [4, 3],
+        "variants": [{"type": "nelson_c", "value": "2097"}, {"type": "ucs", "value": "2f47"}],
+        "frequency": 1,
+        "radicalNames": ["ひへん"],
+        "jlptLevel": 4
+      },
+      "dictionaryReferences": [
+        {"type": "nelson_c", "morohashi": null, "value": "2097"},
+        {"type": "heisig", "morohashi": null, "value": "12"},
+        {"type": "moro", "morohashi": {"volume": 5, "page": 564}, "value": "13733"}
+      ],
+      "queryCodes": [
+        {"type": "skip", "value": "3-3-1"},
+        {"type": "skip", "skipMisclassification": "stroke_count", "value": "3-3-2"},
+        {"type": "four_corner", "value": "6010.0"},
+        {"type": "deroo", "value": "2439"}
+      ],
+      "readingMeaning": {
+        "groups": [
+          {
+            "readings": [
+              {"type": "pinyin", "onType": null, "status": null, "value": "ri4"},
+              {"type": "korean_h", "onType": null, "status": null, "value": "일"},
+              {"type": "ja_on", "onType": "kan", "status": "jy", "value": "ニチ"},
+              {"type": "ja_on", "onType": null, "status": null, "value": "ジツ"},
+              {"type": "ja_kun", "onType": null, "status": null, "value": "ひ"}
+            ],
+            "meanings": [
+              {"lang": "en", "value": "day"},
+              {"lang": "en", "value": "sun"},
+              {"lang": "fr", "value": "jour"}
+            ]
+          }
+        ],
+        "nanori": ["あ", "に"]
+      }
+    }
+  ]
+}`
+
+func TestImporter_Import(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kanjidic2.json")
+	if err := os.WriteFile(path, []byte(testKanjidic), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	entries, err := (&Importer{}).Import(path)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	k := entries[0].(Kanji)
+
+	if !reflect.DeepEqual(k.Nanori, []string{"あ", "に"}) {
+		t.Errorf("Expected nanori [あ に], got %v", k.Nanori)
+	}
+	if k.Radical != 72 || k.NelsonRadical != 73 {
+		t.Errorf("Expected radicals 72 and 73, got %d and %d", k.Radical, k.NelsonRadical)
+	}
+	if k.Stroke != 4 || !reflect.DeepEqual(k.StrokeMiscounts, []int{3}) {
+		t.Errorf("Expected 4 strokes, miscounted as 3, got %d and %v", k.Stroke, k.StrokeMiscounts)
+	}
+	if k.Codepoints["ucs"] != "65e5" || k.Codepoints["jis208"] != "1-38-92" {
+		t.Errorf("Unexpected codepoints %v", k.Codepoints)
+	}
+	if !reflect.DeepEqual(k.RadicalNames, []string{"ひへん"}) {
+		t.Errorf("Unexpected radical names %v", k.RadicalNames)
+	}
+	if !reflect.DeepEqual(k.Variants, []string{"⽇"}) || len(k.VariantRefs) != 2 || k.VariantRefs[0] != (Ref{Type: "nelson_c", Value: "2097"}) {
+		t.Errorf("Unexpected variants %v and %v", k.Variants, k.VariantRefs)
+	}
+	if k.References["heisig"] != "12" || k.References["moro"] != "13733" {
+		t.Errorf("Unexpected references %v", k.References)
+	}
+	if k.Morohashi == nil || *k.Morohashi != (MorohashiRef{Volume: 5, Page: 564}) {
+		t.Errorf("Expected Morohashi volume 5 page 564, got %+v", k.Morohashi)
+	}
+	if k.SKIP() != "3-3-1" || len(k.QueryCodes) != 4 || k.QueryCodes[1].Misclass != "stroke_count" {
+		t.Errorf("Unexpected query codes %+v", k.QueryCodes)
+	}
+	if !reflect.DeepEqual(k.ReadingNotes, []ReadingNote{{Reading: "ニチ", OnType: "kan", Status: "jy"}}) {
+		t.Errorf("Unexpected reading notes %+v", k.ReadingNotes)
+	}
+	if !reflect.DeepEqual(k.OnYomi, []string{"ニチ", "ジツ"}) || !reflect.DeepEqual(k.KunYomi, []string{"ひ"}) {
+		t.Errorf("Unexpected readings %v and %v", k.OnYomi, k.KunYomi)
+	}
+	if !reflect.DeepEqual(k.Pinyin, []string{"ri4"}) || !reflect.DeepEqual(k.KoreanHangul, []string{"일"}) {
+		t.Errorf("Unexpected Chinese and Korean readings %v and %v", k.Pinyin, k.KoreanHangul)
+	}
+	if !reflect.DeepEqual(k.Meanings, []string{"day", "sun"}) || !reflect.DeepEqual(k.OtherMeanings, map[string][]string{"fr": {"jour"}}) {
+		t.Errorf("Unexpected meanings %v and %v", k.Meanings, k.OtherMeanings)
+	}
+}
@@ -15,15 +15,70 @@ type KanjidicTypes struct {
 type Kanji struct {
 	Character string   `json:"c"`
 	NumericID string   `json:"id,omitempty"` // Added numeric ID
-	Meanings  []string `json:"m,omitempty"`
+	Meanings  []string `json:"m,omitempty"`  // English meanings
 	OnYomi    []string `json:"on,omitempty"`
 	KunYomi   []string `json:"kun,omitempty"`
+	Nanori    []string `json:"nanori,omitempty"` // Readings used in names
 	JLPT      int      `json:"jlpt,omitempty"`
 	Grade     int      `json:"grade,omitempty"`
 	Stroke    int      `json:"stroke"`
 	Frequency int      `json:"freq,omitempty"`
-	Radicals  []string `json:"rad,omitempty"`
 	IDS       string   `json:"ids,omitempty"` // Ideographic Description Sequence
+	Variants  []string `json:"var,omitempty"` // Variant characters (国 → 國)
+
+	Codepoints      map[string]string   `json:"cp,omitempty"`    // Encodings by type: ucs, jis208, jis212, jis213
+	Radical         int                 `json:"rad,omitempty"`   // Classical (Kangxi) radical number
+	NelsonRadical   int                 `json:"nrad,omitempty"`  // Radical in Nelson's dictionary, when it differs
+	Parts           []string            `json:"parts,omitempty"` // KRADFILE lookup radicals, from the traditional set of 252
+	RadicalNames    []string            `json:"radn,omitempty"`  // Names of the kanji when used as a radical
+	StrokeMiscounts []int               `json:"strm,omitempty"`  // Common miscounts of Stroke
+	VariantRefs     []Ref               `json:"varr,omitempty"`  // Variants as given, including unresolved dictionary indexes
+	References      map[string]string   `json:"ref,omitempty"`   // Index numbers by dictionary type, e.g. nelson_c, heisig, moro
+	Morohashi       *MorohashiRef       `json:"moro,omitempty"`  // Volume and page of the moro reference
+	QueryCodes      []QueryRef          `json:"q,omitempty"`     // SKIP, four-corner, De Roo and Spahn/Hadamitzky codes
+	ReadingNotes    []ReadingNote       `json:"rn,omitempty"`    // On'yomi types and jōyō status of readings that have them
+	Pinyin          []string            `json:"py,omitempty"`    // Mandarin readings
+	KoreanRomanized []string            `json:"kr,omitempty"`    // Korean readings, romanized
+	KoreanHangul    []string            `json:"kh,omitempty"`    // Korean readings in hangul
+	Vietnamese      []string            `json:"vi,omitempty"`    // Vietnamese readings
+	OtherMeanings   map[string][]string `json:"mlang,omitempty"` // Meanings in other languages, by language code (fr, es, pt)
+}
+
+// Ref is a typed value, such as a variant given as a JIS code or dictionary index
+type Ref struct {
+	Type  string `json:"t"`
+	Value string `json:"v"`
+}
+
+// MorohashiRef locates a kanji in Morohashi's Dai Kan-Wa Jiten
+type MorohashiRef struct {
+	Volume int `json:"vol"`
+	Page   int `json:"p"`
+}
+
+// QueryRef is a lookup code, such as a SKIP code with a misclassification kind
+type QueryRef struct {
+	Type     string `json:"t"` // skip, sh_desc, four_corner, deroo
+	Value    string `json:"v"`
+	Misclass string `json:"mis,omitempty"` // For misclassified SKIP codes: posn, stroke_count, ...
+}
+
+// ReadingNote carries the on'yomi type and status of a reading
+type ReadingNote struct {
+	Reading string `json:"r"`
+	OnType  string `json:"ot,omitempty"` // kan, go, tou or kan'you
+	Status  string `json:"s,omitempty"`  // jy for readings approved for jōyō use
+}
+
+// SKIP returns the kanji's SKIP code, leaving out misclassified ones, or "" if it
+// has none
+func (k Kanji) SKIP() string {
+	for _, q := range k.QueryCodes {
+		if q.Type == "skip" && q.Misclass == "" {
+			return q.Value
+		}
+	}
+	return ""
 }
 
 // GetID returns the unique identifier for this kanji
@@ -0,0 +1,175 @@
+package kanjivg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"kiokun-go/dictionaries/common"
+)
+
+// Importer handles importing a KanjiVG XML release
+type Importer struct{}
+
+// Name returns the name of this importer
+func (i *Importer) Name() string {
+	return "kanjivg"
+}
+
+// Import reads a KanjiVG release. A path with a glob pattern imports the last
+// matching file in name order, which is the latest of dated releases. No match
+// imports no entries, since the release is downloaded separately.
+func (i *Importer) Import(path string) ([]common.Entry, error) {
+	paths, err := filepath.Glob(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	sort.Strings(paths)
+
+	file, err := os.Open(paths[len(paths)-1])
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	characters, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", paths[len(paths)-1], err)
+	}
+	entries := make([]common.Entry, len(characters))
+	for i, c := range characters {
+		entries[i] = c
+	}
+	return entries, nil
+}
+
+// Parse reads the kanji elements of KanjiVG XML, one at a time. Each kanji holds
+// nested g elements for its structure and path elements for its strokes, in order:
+//
+//	<kanji id="kvg:kanji_06d77">
+//	  <g id="kvg:06d77" kvg:element="海">
+//	    <g id="kvg:06d77-g1" kvg:element="氵" kvg:original="水" kvg:position="left" kvg:radical="general">
+//	      <path id="kvg:06d77-s1" kvg:type="㇔" d="M..."/>
+func Parse(r io.Reader) ([]Character, error) {
+	decoder := xml.NewDecoder(r)
+
+	var characters []Character
+	var current *Character
+	var stack []*Group
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "kanji":
+				c, err := newCharacter(attr(t, "id"))
+				if err != nil {
+					return nil, err
+				}
+				current, stack = &c, nil
+			case "g":
+				if current == nil {
+					continue
+				}
+				group := newGroup(t)
+				if len(stack) == 0 {
+					if current.Root == nil {
+						current.Root = group
+					} else {
+						current.Root.Children = append(current.Root.Children, group)
+					}
+				} else {
+					parent := stack[len(stack)-1]
+					parent.Children = append(parent.Children, group)
+				}
+				stack = append(stack, group)
+			case "path":
+				if current == nil {
+					continue
+				}
+				if len(stack) > 0 {
+					group := stack[len(stack)-1]
+					group.Strokes = append(group.Strokes, len(current.Strokes))
+				}
+				current.Strokes = append(current.Strokes, Stroke{Type: attr(t, "type"), Path: attr(t, "d")})
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "g":
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+			case "kanji":
+				if current != nil {
+					characters = append(characters, *current)
+				}
+				current, stack = nil, nil
+			}
+		}
+	}
+	return characters, nil
+}
+
+// newCharacter starts a character from a kanji id such as kvg:kanji_04e00 or
+// kvg:kanji_04e00-Kaisho
+func newCharacter(id string) (Character, error) {
+	id = strings.TrimPrefix(id, "kvg:kanji_")
+	code, variant, _ := strings.Cut(id, "-")
+	cp, err := strconv.ParseInt(code, 16, 32)
+	if err != nil {
+		return Character{}, fmt.Errorf("invalid kanji id %q", id)
+	}
+	return Character{ID: id, Character: string(rune(cp)), Variant: variant}, nil
+}
+
+// newGroup reads the element attributes of a g element
+func newGroup(t xml.StartElement) *Group {
+	group := &Group{
+		Element:  attr(t, "element"),
+		Original: attr(t, "original"),
+		Position: attr(t, "position"),
+		Radical:  attr(t, "radical"),
+		Phon:     attr(t, "phon"),
+	}
+	if part, err := strconv.Atoi(attr(t, "part")); err == nil {
+		group.Part = part
+	}
+	return group
+}
+
+// attr returns an attribute by its local name, ignoring its namespace
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// ByCharacter maps each character to its standard KanjiVG form, leaving out glyph
+// variants. Other entries are ignored.
+func ByCharacter(entries []common.Entry) map[string]Character {
+	characters := make(map[string]Character)
+	for _, entry := range entries {
+		if c, ok := entry.(Character); ok && c.Variant == "" {
+			characters[c.Character] = c
+		}
+	}
+	return characters
+}
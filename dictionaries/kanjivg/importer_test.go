@@ -0,0 +1,114 @@
+package kanjivg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testKanjiVG is an abridged KanjiVG release with 一, 氵-side 池 and a variant of 一
+const testKanjiVG = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE kanjivg [
+<!ELEMENT kanjivg (kanji*)>
+<!ATTLIST kanjivg xmlns:kvg CDATA #FIXED "http://kanjivg.tagaini.net">
+]>
+<kanjivg xmlns:kvg='http://kanjivg.tagaini.net'>
+<kanji id="kvg:kanji_04e00">
+<g id="kvg:04e00" kvg:element="一" kvg:radical="general">
+	<path id="kvg:04e00-s1" kvg:type="㇐" d="M11,54.25c3.19,0.62,6.25,0.75,9.73,0.5"/>
+</g>
+</kanji>
+<kanji id="kvg:kanji_06c60">
+<g id="kvg:06c60" kvg:element="池">
+	<g id="kvg:06c60-g1" kvg:element="氵" kvg:variant="true" kvg:original="水" kvg:position="left" kvg:radical="general">
+		<path id="kvg:06c60-s1" kvg:type="㇔" d="M1,1"/>
+		<path id="kvg:06c60-s2" kvg:type="㇔" d="M2,2"/>
+		<path id="kvg:06c60-s3" kvg:type="㇀" d="M3,3"/>
+	</g>
+	<g id="kvg:06c60-g2" kvg:element="也" kvg:position="right" kvg:phon="也">
+		<path id="kvg:06c60-s4" kvg:type="㇆a" d="M4,4"/>
+		<path id="kvg:06c60-s5" kvg:type="㇑" d="M5,5"/>
+		<path id="kvg:06c60-s6" kvg:type="㇟" d="M6,6"/>
+	</g>
+</g>
+</kanji>
+<kanji id="kvg:kanji_04e00-Kaisho">
+<g id="kvg:04e00-Kaisho" kvg:element="一">
+	<path id="kvg:04e00-Kaisho-s1" kvg:type="㇐" d="M12,54"/>
+</g>
+</kanji>
+</kanjivg>
+`
+
+func TestParse(t *testing.T) {
+	characters, err := Parse(strings.NewReader(testKanjiVG))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if len(characters) != 3 {
+		t.Fatalf("Expected 3 characters, got %d", len(characters))
+	}
+
+	one := characters[0]
+	if one.ID != "04e00" || one.Character != "一" || one.Variant != "" {
+		t.Errorf("Unexpected character %+v", one)
+	}
+	if len(one.Strokes) != 1 || one.Strokes[0] != (Stroke{Type: "㇐", Path: "M11,54.25c3.19,0.62,6.25,0.75,9.73,0.5"}) {
+		t.Errorf("Unexpected strokes %+v", one.Strokes)
+	}
+	if one.Root == nil || one.Root.Element != "一" || one.Root.Radical != "general" || !reflect.DeepEqual(one.Root.Strokes, []int{0}) {
+		t.Errorf("Unexpected root %+v", one.Root)
+	}
+
+	pond := characters[1]
+	if len(pond.Strokes) != 6 || pond.Strokes[3].Type != "㇆a" || pond.Strokes[5].Path != "M6,6" {
+		t.Fatalf("Unexpected strokes %+v", pond.Strokes)
+	}
+	if len(pond.Root.Children) != 2 || len(pond.Root.Strokes) != 0 {
+		t.Fatalf("Expected two elements under 池, got %+v", pond.Root)
+	}
+	water := pond.Root.Children[0]
+	if water.Element != "氵" || water.Original != "水" || water.Position != "left" || !reflect.DeepEqual(water.Strokes, []int{0, 1, 2}) {
+		t.Errorf("Unexpected 氵 element %+v", water)
+	}
+	also := pond.Root.Children[1]
+	if also.Element != "也" || also.Phon != "也" || !reflect.DeepEqual(also.Strokes, []int{3, 4, 5}) {
+		t.Errorf("Unexpected 也 element %+v", also)
+	}
+
+	if variant := characters[2]; variant.Character != "一" || variant.Variant != "Kaisho" || variant.ID != "04e00-Kaisho" {
+		t.Errorf("Unexpected variant %+v", variant)
+	}
+}
+
+func TestImporter_Import(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"kanjivg-20160426.xml": "<kanjivg></kanjivg>",
+		"kanjivg-20230110.xml": testKanjiVG,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	// The latest release is read
+	entries, err := (&Importer{}).Import(filepath.Join(dir, "kanjivg*.xml"))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	characters := ByCharacter(entries)
+	if len(entries) != 3 || len(characters) != 2 {
+		t.Fatalf("Expected 3 entries for 2 characters, got %d and %d", len(entries), len(characters))
+	}
+	if c := characters["一"]; c.Variant != "" || c.Strokes[0].Path != "M11,54.25c3.19,0.62,6.25,0.75,9.73,0.5" {
+		t.Errorf("Expected the standard form of 一, got %+v", c)
+	}
+
+	entries, err = (&Importer{}).Import(filepath.Join(t.TempDir(), "kanjivg*.xml"))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries and no error without a release, got %d and %v", len(entries), err)
+	}
+}
@@ -0,0 +1,10 @@
+package kanjivg
+
+import (
+	"kiokun-go/dictionaries/common"
+)
+
+func init() {
+	// Register the KanjiVG release file, named kanjivg-<date>.xml
+	common.RegisterDictionary("kanjivg", "kanjivg*.xml", &Importer{})
+}
@@ -0,0 +1,40 @@
+package kanjivg
+
+// Character is the KanjiVG stroke data of one character: its strokes in writing order
+// and the tree of elements (components) they make up
+type Character struct {
+	ID        string   `json:"id"`          // Code point in hex with an optional variant suffix, e.g. 04e00 or 04e00-Kaisho
+	Character string   `json:"c"`           // The character itself
+	Variant   string   `json:"v,omitempty"` // Glyph variant such as Kaisho, empty for the standard form
+	Strokes   []Stroke `json:"s"`           // Strokes in writing order
+	Root      *Group   `json:"g,omitempty"` // Element structure
+}
+
+// Stroke is one stroke of a character
+type Stroke struct {
+	Type string `json:"t,omitempty"` // Stroke type, such as ㇐ or ㇑a
+	Path string `json:"d"`           // SVG path data in KanjiVG's 109×109 coordinates
+}
+
+// Group is an element of a character's structure, such as the 氵 of 海. Its strokes
+// are those listed directly and those of its children.
+type Group struct {
+	Element  string   `json:"e,omitempty"`  // The element's character
+	Original string   `json:"o,omitempty"`  // The character the element is a form of, such as 水 for 氵
+	Position string   `json:"p,omitempty"`  // Position in the parent: left, right, top, bottom, ...
+	Radical  string   `json:"r,omitempty"`  // Radical type: general, nelson or tradit
+	Part     int      `json:"pt,omitempty"` // Part number, for elements split by other elements
+	Phon     string   `json:"ph,omitempty"` // Phonetic element
+	Strokes  []int    `json:"s,omitempty"`  // Indexes into Character.Strokes directly in this group
+	Children []*Group `json:"g,omitempty"`  // Subelements, in order
+}
+
+// GetID returns the entry ID
+func (c Character) GetID() string {
+	return c.ID
+}
+
+// GetFilename returns the filename for this entry
+func (c Character) GetFilename() string {
+	return c.ID
+}
@@ -0,0 +1,32 @@
+package kradfile
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// decodeText returns the text of a KRADFILE or RADKFILE, which the EDRDG distributes
+// in EUC-JP. Files already converted to UTF-8 are returned as they are.
+func decodeText(data []byte) (string, error) {
+	if utf8.Valid(data) {
+		return string(data), nil
+	}
+	return decodeEUCJP(data)
+}
+
+// decodeEUCJP decodes EUC-JP text, including the JIS X 0212 kanji of kradfile2.
+// Bytes that are not valid EUC-JP are an error rather than replacement characters.
+func decodeEUCJP(data []byte) (string, error) {
+	decoded, err := japanese.EUCJP.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("error decoding EUC-JP: %v", err)
+	}
+	// The decoder writes U+FFFD for invalid bytes, which EUC-JP cannot encode
+	if i := bytes.IndexRune(decoded, utf8.RuneError); i >= 0 {
+		return "", fmt.Errorf("invalid EUC-JP text after %q", decoded[:i])
+	}
+	return string(decoded), nil
+}
@@ -0,0 +1,159 @@
+package kradfile
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"kiokun-go/dictionaries/common"
+)
+
+// Importer handles importing the EDRDG KRADFILE and RADKFILE
+type Importer struct{}
+
+// Name returns the name of this importer
+func (i *Importer) Name() string {
+	return "kradfile"
+}
+
+// kradSupplements and radkFiles are read from the directory of the kradfile when present:
+// decompositions of the JIS X 0212 kanji, and the radicals with their stroke counts.
+// radkfilex covers the kanji of both kradfiles, so it is preferred over radkfile.
+var (
+	kradSupplements = []string{"kradfile2"}
+	radkFiles       = []string{"radkfilex", "radkfile"}
+)
+
+// Import reads the kradfile at path as Decomposition entries, then kradfile2 and the
+// first radkfile found in the same directory, the latter as Radical entries. Each
+// file may be in EUC-JP, as distributed, or UTF-8. A missing kradfile imports no
+// entries, since the files are downloaded separately from the other dictionaries.
+func (i *Importer) Import(path string) ([]common.Entry, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var entries []common.Entry
+	dir := filepath.Dir(path)
+	for _, p := range append([]string{path}, joinAll(dir, kradSupplements)...) {
+		text, err := readText(p)
+		if os.IsNotExist(err) && p != path {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		decompositions, err := ParseKradfile(text)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", p, err)
+		}
+		for _, d := range decompositions {
+			entries = append(entries, d)
+		}
+	}
+
+	for _, p := range joinAll(dir, radkFiles) {
+		text, err := readText(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		radicals, err := ParseRadkfile(text)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", p, err)
+		}
+		for _, r := range radicals {
+			entries = append(entries, r)
+		}
+		break
+	}
+
+	return entries, nil
+}
+
+// joinAll joins each name to a directory
+func joinAll(dir string, names []string) []string {
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(dir, name)
+	}
+	return paths
+}
+
+// readText reads a file and decodes it from EUC-JP unless it is already UTF-8
+func readText(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	text, err := decodeText(data)
+	if err != nil {
+		return "", fmt.Errorf("error decoding %s: %v", path, err)
+	}
+	return text, nil
+}
+
+// ParseKradfile parses KRADFILE lines such as "亜 : 一 ｜ 口", skipping comments
+func ParseKradfile(text string) ([]Decomposition, error) {
+	var decompositions []Decomposition
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kanji, components, ok := strings.Cut(line, ":")
+		kanji = strings.TrimSpace(kanji)
+		if !ok || kanji == "" {
+			return nil, fmt.Errorf("line %d: expected \"kanji : radicals\", got %q", lineNum, line)
+		}
+		decompositions = append(decompositions, Decomposition{Kanji: kanji, Components: strings.Fields(components)})
+	}
+	return decompositions, scanner.Err()
+}
+
+// ParseRadkfile parses a RADKFILE: each radical starts with a line such as
+// "$ 化 2 js01", giving the radical, its stroke count and an optional image name,
+// followed by lines of the kanji containing it
+func ParseRadkfile(text string) ([]Radical, error) {
+	var radicals []Radical
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "$") {
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("line %d: expected \"$ radical strokes\", got %q", lineNum, line)
+			}
+			strokes, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid stroke count %q", lineNum, fields[2])
+			}
+			radicals = append(radicals, Radical{Radical: fields[1], Strokes: strokes})
+			continue
+		}
+
+		if len(radicals) == 0 {
+			return nil, fmt.Errorf("line %d: kanji before the first radical", lineNum)
+		}
+		current := &radicals[len(radicals)-1]
+		for _, r := range line {
+			current.Kanji = append(current.Kanji, string(r))
+		}
+	}
+	return radicals, scanner.Err()
+}
@@ -0,0 +1,147 @@
+package kradfile
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/kanjidic"
+)
+
+// EUC-JP test files, as the EDRDG distributes them. The kradfile decodes to
+// "亜 : 一 ｜ 口", "唖 : 一 ｜ 口" and "海 : 毋 汁 乙 一"; kradfile2 to "丂 : 一 勹", with
+// 丂 from JIS X 0212.
+const (
+	testKradfile  = "# KRADFILE\n\xb0\xa1 : \xb0\xec \xa1\xc3 \xb8\xfd\n\xb0\xa2 : \xb0\xec \xa1\xc3 \xb8\xfd\n\xb3\xa4 : \xdd\xd9 \xbd\xc1 \xb2\xb5 \xb0\xec\n"
+	testKradfile2 = "\x8f\xb0\xa1 : \xb0\xec \xd2\xb1\n"
+	testRadkfile  = "# RADKFILE\n$ \xb0\xec 1\n\xb0\xa1\xb0\xa2\xb3\xa4\x8f\xb0\xa1\n$ \xa1\xc3 1\n\xb0\xa1\xb0\xa2\n$ \xb8\xfd 3\n\xb0\xa1\xb0\xa2\n$ \xbd\xc1 3 js07\n\xb3\xa4\n"
+)
+
+// writeFiles writes test files into a directory
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+}
+
+func TestImporter_Import(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"kradfile": testKradfile, "kradfile2": testKradfile2, "radkfile": testRadkfile})
+
+	entries, err := (&Importer{}).Import(filepath.Join(dir, "kradfile"))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	var decompositions []Decomposition
+	var radicals []Radical
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case Decomposition:
+			decompositions = append(decompositions, e)
+		case Radical:
+			radicals = append(radicals, e)
+		}
+	}
+
+	expected := []Decomposition{
+		{Kanji: "亜", Components: []string{"一", "｜", "口"}},
+		{Kanji: "唖", Components: []string{"一", "｜", "口"}},
+		{Kanji: "海", Components: []string{"毋", "汁", "乙", "一"}},
+		{Kanji: "丂", Components: []string{"一", "勹"}},
+	}
+	if !reflect.DeepEqual(decompositions, expected) {
+		t.Errorf("Expected decompositions %v, got %v", expected, decompositions)
+	}
+
+	if len(radicals) != 4 {
+		t.Fatalf("Expected 4 radicals, got %v", radicals)
+	}
+	if r := radicals[0]; r.Radical != "一" || r.Strokes != 1 || !reflect.DeepEqual(r.Kanji, []string{"亜", "唖", "海", "丂"}) {
+		t.Errorf("Unexpected first radical %+v", r)
+	}
+	if r := radicals[3]; r.Radical != "汁" || r.Strokes != 3 || !reflect.DeepEqual(r.Kanji, []string{"海"}) {
+		t.Errorf("Unexpected last radical %+v", r)
+	}
+}
+
+func TestImporter_ImportUTF8AndMissing(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"kradfile": "亜 : 一 ｜ 口\n"})
+
+	entries, err := (&Importer{}).Import(filepath.Join(dir, "kradfile"))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(entries) != 1 || !reflect.DeepEqual(entries[0], Decomposition{Kanji: "亜", Components: []string{"一", "｜", "口"}}) {
+		t.Errorf("Unexpected entries %v", entries)
+	}
+
+	entries, err = (&Importer{}).Import(filepath.Join(t.TempDir(), "kradfile"))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries and no error for a missing kradfile, got %d and %v", len(entries), err)
+	}
+}
+
+func TestDecodeEUCJP(t *testing.T) {
+	got, err := decodeEUCJP([]byte("a\xa4\xa2\xb0\xa1\x8e\xb1\x8f\xb0\xa1"))
+	if err != nil {
+		t.Fatalf("decodeEUCJP error: %v", err)
+	}
+	if got != "aあ亜ｱ丂" {
+		t.Errorf("Expected aあ亜ｱ丂, got %s", got)
+	}
+
+	if _, err := decodeEUCJP([]byte("\xb0")); err == nil {
+		t.Error("Expected an error for a truncated character")
+	}
+}
+
+func TestTable(t *testing.T) {
+	entries := []common.Entry{
+		Decomposition{Kanji: "亜", Components: []string{"一", "｜", "口"}},
+		Decomposition{Kanji: "唖", Components: []string{"一", "｜", "口"}},
+		Decomposition{Kanji: "海", Components: []string{"毋", "汁", "乙", "一"}},
+		Radical{Radical: "一", Strokes: 1, Kanji: []string{"亜", "唖", "海"}},
+		Radical{Radical: "｜", Strokes: 1, Kanji: []string{"亜", "唖"}},
+		Radical{Radical: "口", Strokes: 3, Kanji: []string{"亜", "唖"}},
+		Radical{Radical: "汁", Strokes: 3, Kanji: []string{"海"}},
+		kanjidic.Kanji{Character: "亜", Stroke: 7, Frequency: 1509},
+		kanjidic.Kanji{Character: "唖", Stroke: 10},
+		kanjidic.Kanji{Character: "海", Stroke: 9, Frequency: 200},
+	}
+	table := FromEntries(entries)
+
+	if got := table.Components("海"); !reflect.DeepEqual(got, []string{"毋", "汁", "乙", "一"}) {
+		t.Errorf("Unexpected components of 海: %v", got)
+	}
+
+	radicals := table.Radicals()
+	if len(radicals) != 4 || radicals[0].Radical != "一" || radicals[2].Strokes != 3 {
+		t.Fatalf("Unexpected radicals %v", radicals)
+	}
+	// Fewest strokes first
+	if !reflect.DeepEqual(radicals[0].Kanji, []string{"亜", "海", "唖"}) {
+		t.Errorf("Expected 一 to list 亜 海 唖, got %v", radicals[0].Kanji)
+	}
+
+	testCases := []struct {
+		radicals []string
+		expected []string
+	}{
+		{[]string{"一"}, []string{"亜", "海", "唖"}},
+		{[]string{"一", "口"}, []string{"亜", "唖"}},
+		{[]string{"汁", "口"}, nil},
+		{nil, nil},
+	}
+	for _, tc := range testCases {
+		if got := table.Search(tc.radicals...); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Search(%v) = %v, want %v", tc.radicals, got, tc.expected)
+		}
+	}
+}
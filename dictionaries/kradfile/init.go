@@ -0,0 +1,11 @@
+package kradfile
+
+import (
+	"kiokun-go/dictionaries/common"
+)
+
+func init() {
+	// Register the KRADFILE; the importer also reads kradfile2 and radkfile from the
+	// same directory
+	common.RegisterDictionary("kradfile", "kradfile", &Importer{})
+}
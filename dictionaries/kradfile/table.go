@@ -0,0 +1,112 @@
+package kradfile
+
+import (
+	"sort"
+
+	"kiokun-go/components"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/kanjidic"
+)
+
+// Table maps kanji to their KRADFILE radicals and radicals to the kanji containing
+// them, for a multi-radical picker
+type Table struct {
+	components map[string][]string        // Radicals of each kanji
+	radicals   []Radical                  // Radicals in RADKFILE order, with ranked kanji
+	kanji      map[string][]string        // Ranked kanji containing each radical
+	members    map[string]map[string]bool // Set of the kanji containing each radical
+	strokes    map[string]int             // Stroke count of each kanji
+	frequency  map[string]int             // Kanjidic frequency rank of each kanji
+}
+
+// FromEntries builds a table from Decomposition and Radical entries, ranking kanji
+// by the stroke counts and frequencies of Kanjidic entries. Without Radical entries
+// the radicals come from the decompositions, sorted, with unknown stroke counts.
+// Other entries are ignored.
+func FromEntries(entries []common.Entry) *Table {
+	t := &Table{
+		components: make(map[string][]string),
+		kanji:      make(map[string][]string),
+		strokes:    make(map[string]int),
+		frequency:  make(map[string]int),
+		members:    make(map[string]map[string]bool),
+	}
+
+	add := func(radical, kanji string) {
+		if t.members[radical] == nil {
+			t.members[radical] = make(map[string]bool)
+		}
+		if !t.members[radical][kanji] {
+			t.members[radical][kanji] = true
+			t.kanji[radical] = append(t.kanji[radical], kanji)
+		}
+	}
+
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case Decomposition:
+			t.components[e.Kanji] = e.Components
+			for _, c := range e.Components {
+				add(c, e.Kanji)
+			}
+		case Radical:
+			t.radicals = append(t.radicals, Radical{Radical: e.Radical, Strokes: e.Strokes})
+			for _, k := range e.Kanji {
+				add(e.Radical, k)
+			}
+		case kanjidic.Kanji:
+			t.strokes[e.Character] = e.Stroke
+			t.frequency[e.Character] = e.Frequency
+		}
+	}
+
+	if len(t.radicals) == 0 {
+		for radical := range t.kanji {
+			t.radicals = append(t.radicals, Radical{Radical: radical})
+		}
+		sort.Slice(t.radicals, func(i, j int) bool { return t.radicals[i].Radical < t.radicals[j].Radical })
+	}
+	for i := range t.radicals {
+		kanji := t.kanji[t.radicals[i].Radical]
+		components.Rank(kanji, t.strokes, t.frequency)
+		t.radicals[i].Kanji = kanji
+	}
+	return t
+}
+
+// Components returns the KRADFILE radicals of a kanji
+func (t *Table) Components(kanji string) []string {
+	return t.components[kanji]
+}
+
+// Radicals returns every radical with its stroke count and the kanji containing it,
+// fewest strokes first
+func (t *Table) Radicals() []Radical {
+	return t.radicals
+}
+
+// Search returns the kanji containing every one of the given radicals, fewest
+// strokes first, then most frequent first
+func (t *Table) Search(radicals ...string) []string {
+	if len(radicals) == 0 {
+		return nil
+	}
+
+	var result []string
+	for _, kanji := range t.kanji[radicals[0]] {
+		if t.containsAll(kanji, radicals[1:]) {
+			result = append(result, kanji)
+		}
+	}
+	return result
+}
+
+// containsAll reports whether a kanji contains every one of the radicals
+func (t *Table) containsAll(kanji string, radicals []string) bool {
+	for _, radical := range radicals {
+		if !t.members[radical][kanji] {
+			return false
+		}
+	}
+	return true
+}
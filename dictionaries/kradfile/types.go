@@ -0,0 +1,36 @@
+package kradfile
+
+// Decomposition lists the KRADFILE radicals of one kanji, from the traditional set
+// of 252 lookup radicals (亻 written as 化, 氵 as 汁, ...) rather than IDS components
+type Decomposition struct {
+	Kanji      string   `json:"k"`
+	Components []string `json:"c"`
+}
+
+// GetID returns the kanji
+func (d Decomposition) GetID() string {
+	return d.Kanji
+}
+
+// GetFilename returns the filename for this entry
+func (d Decomposition) GetFilename() string {
+	return d.Kanji
+}
+
+// Radical is a RADKFILE lookup radical with its stroke count and the kanji
+// containing it
+type Radical struct {
+	Radical string   `json:"r"`
+	Strokes int      `json:"s"`
+	Kanji   []string `json:"k,omitempty"`
+}
+
+// GetID returns the radical, prefixed so it cannot clash with a kanji's ID
+func (r Radical) GetID() string {
+	return "radical:" + r.Radical
+}
+
+// GetFilename returns the filename for this entry
+func (r Radical) GetFilename() string {
+	return r.GetID()
+}
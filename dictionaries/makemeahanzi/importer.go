@@ -0,0 +1,176 @@
+package makemeahanzi
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/ids"
+)
+
+// Importer handles importing Make Me a Hanzi data
+type Importer struct{}
+
+// Name returns the name of this importer
+func (i *Importer) Name() string {
+	return "makemeahanzi"
+}
+
+// Import reads dictionary.txt and the graphics.txt beside it, joining their lines
+// by character. A missing file imports no entries from it, since the data is
+// downloaded separately.
+func (i *Importer) Import(path string) ([]common.Entry, error) {
+	characters := make(map[string]*Character)
+	for _, p := range []string{path, filepath.Join(filepath.Dir(path), "graphics.txt")} {
+		if err := importFile(p, characters); err != nil {
+			return nil, err
+		}
+	}
+
+	entries := make([]common.Entry, 0, len(characters))
+	for _, c := range characters {
+		entries = append(entries, *c)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].GetID() < entries[j].GetID()
+	})
+	return entries, nil
+}
+
+// importFile reads one file and merges its lines into the characters
+func importFile(path string, characters map[string]*Character) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := Parse(file, characters); err != nil {
+		return fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	return nil
+}
+
+// Parse reads Make Me a Hanzi lines, one JSON object per character, into the
+// characters map. Fields of a character already in the map are kept unless the
+// line sets them, so dictionary.txt and graphics.txt can be read in either order:
+//
+//	{"character":"海","definition":"sea, ocean","pinyin":["hǎi"],"decomposition":"⿰氵每", ...}
+//	{"character":"海","strokes":["M 263 ..."],"medians":[[[247,731],...]]}
+func Parse(r io.Reader, characters map[string]*Character) error {
+	reader := bufio.NewReader(r)
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		if trimmed := strings.TrimSpace(string(line)); trimmed != "" {
+			var c Character
+			if err := json.Unmarshal([]byte(trimmed), &c); err != nil {
+				return fmt.Errorf("line %d: %v", lineNum, err)
+			}
+			if c.Character == "" {
+				return fmt.Errorf("line %d: missing character", lineNum)
+			}
+			merge(characters, c)
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// merge adds the fields of c to its character's entry
+func merge(characters map[string]*Character, c Character) {
+	existing, ok := characters[c.Character]
+	if !ok {
+		c.ID = fmt.Sprintf("%05x", []rune(c.Character)[0])
+		characters[c.Character] = &c
+		return
+	}
+
+	if c.Definition != "" {
+		existing.Definition = c.Definition
+	}
+	if len(c.Pinyin) > 0 {
+		existing.Pinyin = c.Pinyin
+	}
+	if c.Decomposition != "" {
+		existing.Decomposition = c.Decomposition
+	}
+	if c.Radical != "" {
+		existing.Radical = c.Radical
+	}
+	if c.Etymology != nil {
+		existing.Etymology = c.Etymology
+	}
+	if len(c.Matches) > 0 {
+		existing.Matches = c.Matches
+	}
+	if len(c.Strokes) > 0 {
+		existing.Strokes = c.Strokes
+	}
+	if len(c.Medians) > 0 {
+		existing.Medians = c.Medians
+	}
+}
+
+// ByCharacter maps each character to its Make Me a Hanzi data
+func ByCharacter(entries []common.Entry) map[string]Character {
+	characters := make(map[string]Character)
+	for _, entry := range entries {
+		if c, ok := entry.(Character); ok {
+			characters[c.Character] = c
+		}
+	}
+	return characters
+}
+
+// Unknown is the component Make Me a Hanzi writes where it does not know one
+const Unknown = "？"
+
+// Agrees reports whether a Make Me a Hanzi decomposition and an IDS describe the
+// same structure: the same operators with the same components, where an unknown
+// component matches anything. A sequence that does not parse cannot be compared
+// and is taken to agree.
+func Agrees(decomposition, sequence string) bool {
+	a, err := ids.Parse(decomposition)
+	if err != nil {
+		return true
+	}
+	b, err := ids.Parse(sequence)
+	if err != nil {
+		return true
+	}
+	return sameTree(a, b)
+}
+
+// sameTree compares two decomposition trees node by node
+func sameTree(a, b *ids.Node) bool {
+	if a.Component == Unknown || b.Component == Unknown {
+		return true
+	}
+	if a.Operator != b.Operator || len(a.Children) != len(b.Children) {
+		return false
+	}
+	if a.Operator == 0 {
+		return a.Component == b.Component
+	}
+	for i := range a.Children {
+		if !sameTree(a.Children[i], b.Children[i]) {
+			return false
+		}
+	}
+	return true
+}
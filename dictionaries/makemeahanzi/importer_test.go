@@ -0,0 +1,85 @@
+package makemeahanzi
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testDictionary is an abridged dictionary.txt with a pictographic and a
+// pictophonetic character
+const testDictionary = `{"character":"水","definition":"water, liquid, lotion, juice","pinyin":["shuǐ"],"decomposition":"？","radical":"水","etymology":{"type":"pictographic","hint":"Water flowing"},"matches":[null,null,null,null]}
+{"character":"海","definition":"sea, ocean; maritime","pinyin":["hǎi"],"decomposition":"⿰氵每","radical":"氵","etymology":{"type":"pictophonetic","phonetic":"每","semantic":"氵","hint":"water"},"matches":[[0],[0],[0],[1],[1],[1],[1],[1],[1],[1]]}
+`
+
+// testGraphics is the matching graphics.txt, with strokes for 水 only
+const testGraphics = `{"character":"水","strokes":["M 500 800 Q 520 700 510 100","M 200 500 L 400 450","M 300 300 L 450 500","M 600 500 L 850 200"],"medians":[[[500,800],[510,100]],[[200,500],[400,450]],[[300,300],[450,500]],[[600,500],[850,200]]]}
+`
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "dictionary.txt"), []byte(testDictionary), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "graphics.txt"), []byte(testGraphics), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := (&Importer{}).Import(filepath.Join(dir, "dictionary.txt"))
+	if err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+	characters := ByCharacter(entries)
+	if len(characters) != 2 {
+		t.Fatalf("Expected 2 characters, got %d", len(characters))
+	}
+
+	water := characters["水"]
+	if water.ID != "06c34" || water.Etymology == nil || water.Etymology.Type != "pictographic" {
+		t.Errorf("Unexpected entry for 水: %+v", water)
+	}
+	if len(water.Strokes) != 4 || !reflect.DeepEqual(water.Medians[0], [][2]int{{500, 800}, {510, 100}}) {
+		t.Errorf("Expected the graphics of 水, got %v %v", water.Strokes, water.Medians)
+	}
+	if g := water.Graphics(); g == nil || g.Character != "水" || len(g.Matches) != 4 || g.Matches[0] != nil {
+		t.Errorf("Unexpected graphics for 水: %+v", g)
+	}
+
+	sea := characters["海"]
+	if sea.Decomposition != "⿰氵每" || sea.Radical != "氵" || sea.Pinyin[0] != "hǎi" {
+		t.Errorf("Unexpected entry for 海: %+v", sea)
+	}
+	if e := sea.Etymology; e == nil || e.Type != "pictophonetic" || e.Semantic != "氵" || e.Phonetic != "每" || e.Hint != "water" {
+		t.Errorf("Unexpected etymology for 海: %+v", e)
+	}
+	if !reflect.DeepEqual(sea.Matches[3], []int{1}) || sea.Graphics() != nil {
+		t.Errorf("Expected matches and no graphics for 海, got %v %+v", sea.Matches, sea.Graphics())
+	}
+}
+
+func TestImportMissingFiles(t *testing.T) {
+	entries, err := (&Importer{}).Import(filepath.Join(t.TempDir(), "dictionary.txt"))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries without source files, got %d (%v)", len(entries), err)
+	}
+}
+
+func TestAgrees(t *testing.T) {
+	tests := []struct {
+		decomposition, ids string
+		want               bool
+	}{
+		{"⿰氵每", "⿰氵每", true},
+		{"⿰氵？", "⿰氵⿱𠂉母", true},
+		{"？", "水", true},
+		{"⿱氵每", "⿰氵每", false},
+		{"⿰氵母", "⿰氵每", false},
+		{"⿰氵", "⿰氵每", true}, // Incomplete, cannot be compared
+	}
+	for _, tt := range tests {
+		if got := Agrees(tt.decomposition, tt.ids); got != tt.want {
+			t.Errorf("Agrees(%q, %q) = %v, want %v", tt.decomposition, tt.ids, got, tt.want)
+		}
+	}
+}
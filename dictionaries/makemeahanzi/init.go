@@ -0,0 +1,10 @@
+package makemeahanzi
+
+import (
+	"kiokun-go/dictionaries/common"
+)
+
+func init() {
+	// Register dictionary.txt; graphics.txt is read from the same directory
+	common.RegisterDictionary("makemeahanzi", "dictionary.txt", &Importer{})
+}
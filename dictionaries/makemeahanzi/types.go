@@ -0,0 +1,48 @@
+package makemeahanzi
+
+import (
+	"kiokun-go/dictionaries/chinese_chars"
+)
+
+// Character is a Make Me a Hanzi character, joining its dictionary.txt and
+// graphics.txt lines. Field names follow the source files so both decode into it.
+type Character struct {
+	ID            string                   `json:"id"`                      // Code point in hex, e.g. 06d77
+	Character     string                   `json:"character"`               // The character itself
+	Definition    string                   `json:"definition,omitempty"`    // Short English definition
+	Pinyin        []string                 `json:"pinyin,omitempty"`        // Readings with tone marks
+	Decomposition string                   `json:"decomposition,omitempty"` // IDS with ？ for unknown components
+	Radical       string                   `json:"radical,omitempty"`       // Radical as written in the character
+	Etymology     *chinese_chars.Etymology `json:"etymology,omitempty"`
+	Matches       [][]int                  `json:"matches,omitempty"` // Path in the decomposition of each stroke's component, nil if none
+	Strokes       []string                 `json:"strokes,omitempty"` // SVG path of each stroke outline, in writing order
+	Medians       [][][2]int               `json:"medians,omitempty"` // Points along the middle of each stroke
+}
+
+// GetID returns the entry ID
+func (c Character) GetID() string {
+	return c.ID
+}
+
+// GetFilename returns the filename for this entry
+func (c Character) GetFilename() string {
+	return c.ID
+}
+
+// Graphics is the stroke data of a character, written as a companion file of its
+// Chinese character entry since it is much larger than the entry itself. Paths and
+// medians use a 1024×1024 grid with the y axis pointing up, offset by 900.
+type Graphics struct {
+	Character string     `json:"c"`
+	Strokes   []string   `json:"s"`
+	Medians   [][][2]int `json:"md,omitempty"`
+	Matches   [][]int    `json:"m,omitempty"`
+}
+
+// Graphics returns the stroke data of the character, or nil if graphics.txt has none
+func (c Character) Graphics() *Graphics {
+	if len(c.Strokes) == 0 {
+		return nil
+	}
+	return &Graphics{Character: c.Character, Strokes: c.Strokes, Medians: c.Medians, Matches: c.Matches}
+}
@@ -0,0 +1,465 @@
+package difficulty
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"unicode"
+
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/chinese_words"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/jmdict"
+	"kiokun-go/dictionaries/kanjidic"
+	"kiokun-go/lookup"
+)
+
+// hardHSKLevel is the lowest HSK level counted as hard
+const hardHSKLevel = 5
+
+// hardCharRank is the frequency rank past which a Chinese character outside HSK is
+// counted as hard
+const hardCharRank = 3000
+
+// Report is the vocabulary and character difficulty profile of a text
+type Report struct {
+	Language         string   `json:"language"`         // "ja" if the text contains kana, otherwise "zh"
+	Words            int      `json:"words"`            // Word tokens, excluding punctuation and other text
+	Characters       int      `json:"characters"`       // Han characters
+	KanjiByJLPT      []Bucket `json:"kanjiByJlpt"`      // Japanese: Han characters by Kanjidic JLPT level (old 4-level scale)
+	KanjiByGrade     []Bucket `json:"kanjiByGrade"`     // Japanese: Han characters by Kanjidic school grade
+	CharsByHSK       []Bucket `json:"charsByHsk"`       // Chinese: Han characters by HSK level
+	CharsByFrequency []Bucket `json:"charsByFrequency"` // Chinese: Han characters by frequency rank
+	WordsByUsage     []Bucket `json:"wordsByUsage"`     // Japanese words by JMdict commonness
+	WordsByHSK       []Bucket `json:"wordsByHsk"`       // Chinese words by HSK level
+	Hard             []Item   `json:"hard"`             // Hard items, once each in order of appearance
+}
+
+// Bucket counts the occurrences that fall in one level
+type Bucket struct {
+	Label string  `json:"label"`
+	Count int     `json:"count"`
+	Share float64 `json:"share"` // Count as a fraction of all counted occurrences
+}
+
+// Item is a hard character or word with the entries it matched
+type Item struct {
+	Text    string         `json:"text"`
+	Kind    string         `json:"kind"` // "kanji", "hanzi", "word" or "unknown"
+	Reason  string         `json:"reason"`
+	Entries []common.Entry `json:"entries,omitempty"`
+}
+
+// counter tallies occurrences by label, remembering the order of the labels
+type counter struct {
+	counts map[string]int
+	order  []string
+	total  int
+}
+
+// newCounter returns a counter whose buckets follow the given labels
+func newCounter(labels ...string) *counter {
+	return &counter{counts: make(map[string]int), order: labels}
+}
+
+// add counts one occurrence
+func (c *counter) add(label string) {
+	c.counts[label]++
+	c.total++
+}
+
+// buckets returns the non-empty buckets in label order
+func (c *counter) buckets() []Bucket {
+	result := []Bucket{}
+	for _, label := range c.order {
+		if n := c.counts[label]; n > 0 {
+			result = append(result, Bucket{Label: label, Count: n, Share: float64(n) / float64(c.total)})
+		}
+	}
+	return result
+}
+
+// analyzer holds the state of one analysis
+type analyzer struct {
+	dict   *lookup.Dictionary
+	report *Report
+	kanji  map[rune]*kanjidic.Kanji
+	hanzi  map[rune]*chinese_chars.ChineseCharEntry
+	seen   map[string]bool // Hard items already listed
+}
+
+// Analyze segments a text with lookup.Dictionary.Scan and profiles its characters
+// and words. In Japanese text, Han characters are counted by their Kanjidic JLPT
+// level and grade and words by JMdict commonness; in Chinese text, Han characters
+// are counted by their HSK level and frequency rank and words by HSK level. Kanji
+// outside the jōyō list, rare Chinese characters outside HSK, uncommon Japanese
+// words, Chinese words at HSK 5 or above or outside HSK, and words not in the
+// dictionary are hard.
+func Analyze(dict *lookup.Dictionary, text string) (*Report, error) {
+	a := &analyzer{
+		dict:   dict,
+		report: &Report{Language: "zh"},
+		kanji:  make(map[rune]*kanjidic.Kanji),
+		hanzi:  make(map[rune]*chinese_chars.ChineseCharEntry),
+		seen:   make(map[string]bool),
+	}
+	for _, r := range text {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana) {
+			a.report.Language = "ja"
+			break
+		}
+	}
+
+	tokens, err := dict.Scan(text)
+	if err != nil {
+		return nil, err
+	}
+
+	jlpt := newCounter("JLPT 4", "JLPT 3", "JLPT 2", "JLPT 1", "none")
+	grade := newCounter("grade 1", "grade 2", "grade 3", "grade 4", "grade 5", "grade 6", "secondary", "jinmeiyo", "none")
+	usage := newCounter("common", "uncommon", "unknown")
+	hsk := newCounter("HSK 1", "HSK 2", "HSK 3", "HSK 4", "HSK 5", "HSK 6", "HSK 7-9", "none", "unknown")
+	charHSK := newCounter("HSK 1", "HSK 2", "HSK 3", "HSK 4", "HSK 5", "HSK 6", "HSK 7-9", "none")
+	charRank := newCounter("top 1000", "top 2000", "top 3000", "top 5000", "rarer", "unknown")
+
+	for _, token := range tokens {
+		if !isWordText(token.Text) {
+			continue
+		}
+		a.report.Words++
+
+		for _, r := range token.Text {
+			if !unicode.Is(unicode.Han, r) {
+				continue
+			}
+			if a.report.Language == "ja" {
+				err = a.addKanji(r, jlpt, grade)
+			} else {
+				err = a.addHanzi(r, charHSK, charRank)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if a.report.Language == "ja" {
+			err = a.addJapaneseWord(token, usage)
+		} else {
+			err = a.addChineseWord(token, hsk)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if a.report.Language == "ja" {
+		a.report.KanjiByJLPT = jlpt.buckets()
+		a.report.KanjiByGrade = grade.buckets()
+		a.report.WordsByUsage = usage.buckets()
+	} else {
+		a.report.CharsByHSK = charHSK.buckets()
+		a.report.CharsByFrequency = charRank.buckets()
+		a.report.WordsByHSK = hsk.buckets()
+	}
+	if a.report.Hard == nil {
+		a.report.Hard = []Item{}
+	}
+	return a.report, nil
+}
+
+// addKanji counts one Han character and lists it if it is outside the jōyō kanji
+func (a *analyzer) addKanji(r rune, jlpt, grade *counter) error {
+	a.report.Characters++
+
+	k, err := a.kanjidic(r)
+	if err != nil {
+		return err
+	}
+	if k == nil {
+		jlpt.add("none")
+		grade.add("none")
+		a.addHard(Item{Text: string(r), Kind: "kanji", Reason: "not in Kanjidic"})
+		return nil
+	}
+
+	if k.JLPT >= 1 && k.JLPT <= 4 {
+		jlpt.add(fmt.Sprintf("JLPT %d", k.JLPT))
+	} else {
+		jlpt.add("none")
+	}
+
+	switch {
+	case k.Grade >= 1 && k.Grade <= 6:
+		grade.add(fmt.Sprintf("grade %d", k.Grade))
+	case k.Grade == 8:
+		grade.add("secondary")
+	case k.Grade == 9 || k.Grade == 10:
+		grade.add("jinmeiyo")
+		a.addHard(Item{Text: string(r), Kind: "kanji", Reason: "jinmeiyō kanji", Entries: []common.Entry{*k}})
+	default:
+		grade.add("none")
+		a.addHard(Item{Text: string(r), Kind: "kanji", Reason: "not a jōyō kanji", Entries: []common.Entry{*k}})
+	}
+	return nil
+}
+
+// addHanzi counts one Han character of Chinese text by its HSK level and frequency
+// rank, and lists it if it is outside HSK and rarer than hardCharRank
+func (a *analyzer) addHanzi(r rune, hsk, rank *counter) error {
+	a.report.Characters++
+
+	c, err := a.chineseChar(r)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		hsk.add("none")
+		rank.add("unknown")
+		a.addHard(Item{Text: string(r), Kind: "hanzi", Reason: "not in the Chinese character dictionary"})
+		return nil
+	}
+
+	level, n := 0, 0
+	if stats := c.Statistics; stats != nil {
+		level = stats.HskLevel
+		n = stats.BookCharRank
+		if n == 0 {
+			n = stats.MovieCharRank
+		}
+	}
+
+	switch {
+	case level >= 7:
+		hsk.add("HSK 7-9")
+	case level >= 1:
+		hsk.add(fmt.Sprintf("HSK %d", level))
+	default:
+		hsk.add("none")
+	}
+
+	switch {
+	case n == 0:
+		rank.add("unknown")
+	case n <= 1000:
+		rank.add("top 1000")
+	case n <= 2000:
+		rank.add("top 2000")
+	case n <= 3000:
+		rank.add("top 3000")
+	case n <= 5000:
+		rank.add("top 5000")
+	default:
+		rank.add("rarer")
+	}
+
+	if level == 0 && (n == 0 || n > hardCharRank) {
+		reason := "not in HSK, no frequency rank"
+		if n > 0 {
+			reason = fmt.Sprintf("not in HSK, frequency rank %d", n)
+		}
+		a.addHard(Item{Text: string(r), Kind: "hanzi", Reason: reason, Entries: []common.Entry{*c}})
+	}
+	return nil
+}
+
+// addJapaneseWord counts a token by the commonness of its JMdict entries
+func (a *analyzer) addJapaneseWord(token lookup.Token, usage *counter) error {
+	entries, err := a.entries(token, "j")
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		usage.add("unknown")
+		a.addHard(Item{Text: token.Text, Kind: "unknown", Reason: "not in JMdict"})
+		return nil
+	}
+
+	for _, e := range entries {
+		if isCommon(e.(jmdict.Word)) {
+			usage.add("common")
+			return nil
+		}
+	}
+	usage.add("uncommon")
+	a.addHard(Item{Text: wordText(token), Kind: "word", Reason: "no common form", Entries: entries})
+	return nil
+}
+
+// addChineseWord counts a token by the lowest HSK level among its word entries
+func (a *analyzer) addChineseWord(token lookup.Token, hsk *counter) error {
+	entries, err := a.entries(token, "w")
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		hsk.add("unknown")
+		a.addHard(Item{Text: token.Text, Kind: "unknown", Reason: "not in the Chinese word dictionary"})
+		return nil
+	}
+
+	level := 0
+	for _, e := range entries {
+		if l := e.(chinese_words.ChineseWordEntry).HskLevel; l > 0 && (level == 0 || l < level) {
+			level = l
+		}
+	}
+
+	label := fmt.Sprintf("HSK %d", level)
+	if level >= 7 {
+		label = "HSK 7-9"
+	}
+
+	switch {
+	case level == 0:
+		hsk.add("none")
+		a.addHard(Item{Text: token.Text, Kind: "word", Reason: "not in HSK", Entries: entries})
+	case level >= hardHSKLevel:
+		hsk.add(label)
+		a.addHard(Item{Text: token.Text, Kind: "word", Reason: label, Entries: entries})
+	default:
+		hsk.add(label)
+	}
+	return nil
+}
+
+// entries loads a token's exact matches of one dictionary type
+func (a *analyzer) entries(token lookup.Token, dictType string) ([]common.Entry, error) {
+	if token.Match == nil {
+		return nil, nil
+	}
+	var entries []common.Entry
+	for _, id := range token.Match.E[dictType] {
+		e, err := a.dict.Entry(dictType, id)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// kanjidic loads a character's Kanjidic entry, or nil if it has none
+func (a *analyzer) kanjidic(r rune) (*kanjidic.Kanji, error) {
+	if k, ok := a.kanji[r]; ok {
+		return k, nil
+	}
+
+	var result *kanjidic.Kanji
+	entry, err := a.dict.Index(string(r))
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && len(entry.E["d"]) > 0 {
+		e, err := a.dict.Entry("d", entry.E["d"][0])
+		if err != nil {
+			return nil, err
+		}
+		k := e.(kanjidic.Kanji)
+		result = &k
+	}
+
+	a.kanji[r] = result
+	return result, nil
+}
+
+// chineseChar loads a character's Chinese character entry, or nil if it has none.
+// Simplified characters are indexed too, so 学 finds the entry of 學.
+func (a *analyzer) chineseChar(r rune) (*chinese_chars.ChineseCharEntry, error) {
+	if c, ok := a.hanzi[r]; ok {
+		return c, nil
+	}
+
+	var result *chinese_chars.ChineseCharEntry
+	entry, err := a.dict.Index(string(r))
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && len(entry.E["c"]) > 0 {
+		e, err := a.dict.Entry("c", entry.E["c"][0])
+		if err != nil {
+			return nil, err
+		}
+		c := e.(chinese_chars.ChineseCharEntry)
+		result = &c
+	}
+
+	a.hanzi[r] = result
+	return result, nil
+}
+
+// addHard lists a hard item the first time it appears
+func (a *analyzer) addHard(item Item) {
+	key := item.Kind + "\x00" + item.Text
+	if a.seen[key] {
+		return
+	}
+	a.seen[key] = true
+	a.report.Hard = append(a.report.Hard, item)
+}
+
+// isCommon reports whether a word has any common kanji or kana form
+func isCommon(w jmdict.Word) bool {
+	for _, k := range w.Kanji {
+		if k.Common {
+			return true
+		}
+	}
+	for _, k := range w.Kana {
+		if k.Common {
+			return true
+		}
+	}
+	return false
+}
+
+// wordText returns the dictionary form of a token (食べる for 食べなかった)
+func wordText(token lookup.Token) string {
+	if token.Base != "" {
+		return token.Base
+	}
+	return token.Text
+}
+
+// isWordText reports whether a token is a word rather than punctuation or other text
+func isWordText(text string) bool {
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes the report in a human-readable form
+func (r *Report) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "Language: %s\n", r.Language)
+	fmt.Fprintf(w, "Words: %d\n", r.Words)
+	fmt.Fprintf(w, "Han characters: %d\n", r.Characters)
+
+	section := func(title string, buckets []Bucket) {
+		if len(buckets) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n%s:\n", title)
+		for _, b := range buckets {
+			fmt.Fprintf(w, "  %-10s %6d  %5.1f%%\n", b.Label, b.Count, b.Share*100)
+		}
+	}
+	section("Kanji by JLPT level", r.KanjiByJLPT)
+	section("Kanji by grade", r.KanjiByGrade)
+	section("Characters by HSK level", r.CharsByHSK)
+	section("Characters by frequency", r.CharsByFrequency)
+	section("Words by commonness", r.WordsByUsage)
+	section("Words by HSK level", r.WordsByHSK)
+
+	if len(r.Hard) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nHard items (%d):\n", len(r.Hard))
+	items := make([]Item, len(r.Hard))
+	copy(items, r.Hard)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Kind < items[j].Kind })
+	for _, item := range items {
+		fmt.Fprintf(w, "  %-8s %s (%s)\n", item.Kind, item.Text, item.Reason)
+	}
+}
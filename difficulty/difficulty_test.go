@@ -0,0 +1,155 @@
+package difficulty
+
+import (
+	"path/filepath"
+	"testing"
+
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/chinese_words"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/jmdict"
+	"kiokun-go/dictionaries/kanjidic"
+	"kiokun-go/lookup"
+	"kiokun-go/processor"
+)
+
+// buildTestDictionary writes a small sharded dictionary into a temp directory
+func buildTestDictionary(t *testing.T, entries []common.Entry) *lookup.Dictionary {
+	t.Helper()
+
+	baseDir := filepath.Join(t.TempDir(), "dict")
+	p, err := processor.NewShardedIndexProcessor(baseDir, 1)
+	if err != nil {
+		t.Fatalf("Error creating processor: %v", err)
+	}
+	if err := p.ProcessEntries(entries); err != nil {
+		t.Fatalf("Error processing entries: %v", err)
+	}
+	if err := p.WriteToFiles(); err != nil {
+		t.Fatalf("Error writing files: %v", err)
+	}
+
+	d, err := lookup.Open(baseDir)
+	if err != nil {
+		t.Fatalf("Error opening dictionary: %v", err)
+	}
+	return d
+}
+
+// bucketCounts flattens buckets for comparison
+func bucketCounts(buckets []Bucket) map[string]int {
+	counts := make(map[string]int)
+	for _, b := range buckets {
+		counts[b.Label] = b.Count
+	}
+	return counts
+}
+
+func TestAnalyzeJapanese(t *testing.T) {
+	d := buildTestDictionary(t, []common.Entry{
+		jmdict.Word{
+			ID:    "1464530",
+			Kanji: []jmdict.KanjiEntry{{Text: "日本", Common: true}},
+			Kana:  []jmdict.KanaEntry{{Text: "にほん", Common: true}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"n"}}},
+		},
+		jmdict.Word{
+			ID:    "2029010",
+			Kana:  []jmdict.KanaEntry{{Text: "の", Common: true}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"prt"}}},
+		},
+		jmdict.Word{
+			ID:    "1579110",
+			Kanji: []jmdict.KanjiEntry{{Text: "薔薇"}},
+			Kana:  []jmdict.KanaEntry{{Text: "ばら"}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"n"}}},
+		},
+		kanjidic.Kanji{Character: "日", NumericID: "1", JLPT: 4, Grade: 1},
+		kanjidic.Kanji{Character: "本", NumericID: "2", JLPT: 4, Grade: 1},
+		kanjidic.Kanji{Character: "薔", NumericID: "3"},
+		kanjidic.Kanji{Character: "薇", NumericID: "4"},
+	})
+
+	report, err := Analyze(d, "日本の薔薇。")
+	if err != nil {
+		t.Fatalf("Analyze error: %v", err)
+	}
+
+	if report.Language != "ja" || report.Words != 3 || report.Characters != 4 {
+		t.Errorf("Unexpected totals: %+v", report)
+	}
+	if got := bucketCounts(report.KanjiByJLPT); got["JLPT 4"] != 2 || got["none"] != 2 {
+		t.Errorf("Unexpected JLPT buckets: %+v", report.KanjiByJLPT)
+	}
+	if got := bucketCounts(report.KanjiByGrade); got["grade 1"] != 2 || got["none"] != 2 {
+		t.Errorf("Unexpected grade buckets: %+v", report.KanjiByGrade)
+	}
+	if got := bucketCounts(report.WordsByUsage); got["common"] != 2 || got["uncommon"] != 1 {
+		t.Errorf("Unexpected usage buckets: %+v", report.WordsByUsage)
+	}
+
+	var hard []string
+	for _, item := range report.Hard {
+		hard = append(hard, item.Kind+":"+item.Text)
+	}
+	expected := []string{"kanji:薔", "kanji:薇", "word:薔薇"}
+	if len(hard) != len(expected) {
+		t.Fatalf("Hard items = %v, want %v", hard, expected)
+	}
+	for i := range expected {
+		if hard[i] != expected[i] {
+			t.Errorf("Hard items = %v, want %v", hard, expected)
+		}
+	}
+}
+
+func TestAnalyzeChinese(t *testing.T) {
+	d := buildTestDictionary(t, []common.Entry{
+		chinese_words.ChineseWordEntry{ID: "30001", Traditional: "中文", Simplified: "中文", HskLevel: 1},
+		chinese_words.ChineseWordEntry{ID: "30002", Traditional: "學習", Simplified: "学习", HskLevel: 1},
+		chinese_words.ChineseWordEntry{ID: "30003", Traditional: "繁瑣", Simplified: "繁琐", HskLevel: 6},
+		chinese_chars.ChineseCharEntry{ID: "20001", Traditional: "學", Simplified: "学", Statistics: &chinese_chars.Statistics{HskLevel: 1, BookCharRank: 70}},
+		chinese_chars.ChineseCharEntry{ID: "20002", Traditional: "習", Simplified: "习", Statistics: &chinese_chars.Statistics{HskLevel: 1, BookCharRank: 700}},
+		chinese_chars.ChineseCharEntry{ID: "20003", Traditional: "中", Simplified: "中", Statistics: &chinese_chars.Statistics{HskLevel: 1, BookCharRank: 14}},
+		chinese_chars.ChineseCharEntry{ID: "20004", Traditional: "文", Simplified: "文", Statistics: &chinese_chars.Statistics{HskLevel: 1, BookCharRank: 200}},
+		chinese_chars.ChineseCharEntry{ID: "20005", Traditional: "很", Simplified: "很", Statistics: &chinese_chars.Statistics{HskLevel: 1, MovieCharRank: 30}},
+		chinese_chars.ChineseCharEntry{ID: "20006", Traditional: "繁", Simplified: "繁", Statistics: &chinese_chars.Statistics{HskLevel: 6, BookCharRank: 1500}},
+		chinese_chars.ChineseCharEntry{ID: "20007", Traditional: "瑣", Simplified: "琐", Statistics: &chinese_chars.Statistics{BookCharRank: 3800}},
+	})
+
+	report, err := Analyze(d, "学习中文很繁琐")
+	if err != nil {
+		t.Fatalf("Analyze error: %v", err)
+	}
+
+	if report.Language != "zh" {
+		t.Errorf("Language = %s, want zh", report.Language)
+	}
+	if got := bucketCounts(report.WordsByHSK); got["HSK 1"] != 2 || got["HSK 6"] != 1 || got["unknown"] != 1 {
+		t.Errorf("Unexpected HSK buckets: %+v", report.WordsByHSK)
+	}
+	if len(report.KanjiByJLPT) != 0 || len(report.KanjiByGrade) != 0 {
+		t.Errorf("Expected no Kanjidic buckets for Chinese text, got %+v %+v", report.KanjiByJLPT, report.KanjiByGrade)
+	}
+	if got := bucketCounts(report.CharsByHSK); got["HSK 1"] != 5 || got["HSK 6"] != 1 || got["none"] != 1 {
+		t.Errorf("Unexpected character HSK buckets: %+v", report.CharsByHSK)
+	}
+	if got := bucketCounts(report.CharsByFrequency); got["top 1000"] != 5 || got["top 2000"] != 1 || got["top 5000"] != 1 {
+		t.Errorf("Unexpected character frequency buckets: %+v", report.CharsByFrequency)
+	}
+
+	// Only the rare character outside HSK is hard; Kanjidic is not consulted
+	var hard []string
+	for _, item := range report.Hard {
+		hard = append(hard, item.Kind+":"+item.Text)
+	}
+	expected := []string{"unknown:很", "hanzi:琐", "word:繁琐"}
+	if len(hard) != len(expected) {
+		t.Fatalf("Hard items = %v, want %v", hard, expected)
+	}
+	for i := range expected {
+		if hard[i] != expected[i] {
+			t.Errorf("Hard items = %v, want %v", hard, expected)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package furigana
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/jmdict"
+	"kiokun-go/dictionaries/kanjidic"
+	"kiokun-go/romaji"
+)
+
+// Readings maps a kanji to its candidate readings in hiragana, longest first.
+// Candidates include okurigana-less kun'yomi stems and rendaku/gemination variants.
+type Readings map[rune][]string
+
+// iterationMark repeats the previous kanji (人々 ひとびと)
+const iterationMark = '々'
+
+// ReadingsFromKanjidic builds the reading table from Kanjidic entries
+func ReadingsFromKanjidic(entries []common.Entry) Readings {
+	readings := make(Readings)
+	for _, entry := range entries {
+		k, ok := entry.(kanjidic.Kanji)
+		if !ok {
+			continue
+		}
+		readings.AddKanjidic(k)
+	}
+	return readings
+}
+
+// AddKanjidic registers the readings of one Kanjidic entry
+func (r Readings) AddKanjidic(k kanjidic.Kanji) {
+	runes := []rune(k.Character)
+	if len(runes) != 1 {
+		return
+	}
+	// Nanori cover compound readings such as 日本 に
+	r.Add(runes[0], k.OnYomi, k.KunYomi, k.Nanori)
+}
+
+// Add registers a kanji's on'yomi (katakana), kun'yomi (Kanjidic notation, e.g. た.べる, -あ.がる)
+// and nanori (name readings)
+func (r Readings) Add(kanji rune, onYomi, kunYomi, nanori []string) {
+	var bases []string
+	for _, on := range onYomi {
+		bases = append(bases, romaji.ToHiragana(strings.Trim(on, "-")))
+	}
+	for _, name := range nanori {
+		bases = append(bases, romaji.ToHiragana(name))
+	}
+	for _, kun := range kunYomi {
+		kun = strings.Trim(kun, "-")
+		stem, okurigana, hasOkurigana := strings.Cut(kun, ".")
+		bases = append(bases, stem)
+		if !hasOkurigana {
+			continue
+		}
+		bases = append(bases, stem+okurigana)
+
+		// Okurigana is often dropped in compounds: 取引 (と.る → とり), 受付 (う.ける → うけ)
+		okuri := []rune(okurigana)
+		last := okuri[len(okuri)-1]
+		bases = append(bases, stem+string(okuri[:len(okuri)-1]))
+		if i, ok := continuative[last]; ok {
+			bases = append(bases, stem+string(okuri[:len(okuri)-1])+string(i))
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range r[kanji] {
+		seen[c] = true
+	}
+	add := func(s string) {
+		if s != "" && !seen[s] {
+			seen[s] = true
+			r[kanji] = append(r[kanji], s)
+		}
+	}
+	for _, base := range bases {
+		for _, v := range soundChanges(base) {
+			add(v)
+		}
+	}
+
+	sort.SliceStable(r[kanji], func(i, j int) bool {
+		return len([]rune(r[kanji][i])) > len([]rune(r[kanji][j]))
+	})
+}
+
+// continuative maps a u-row verb ending to its i-row continuative form
+var continuative = map[rune]rune{
+	'う': 'い', 'く': 'き', 'ぐ': 'ぎ', 'す': 'し', 'つ': 'ち',
+	'ぬ': 'に', 'ぶ': 'び', 'む': 'み', 'る': 'り',
+}
+
+// rendaku maps an unvoiced initial kana to its voiced (and for h-row, half-voiced) forms
+var rendaku = map[rune][]rune{
+	'か': {'が'}, 'き': {'ぎ'}, 'く': {'ぐ'}, 'け': {'げ'}, 'こ': {'ご'},
+	'さ': {'ざ'}, 'し': {'じ'}, 'す': {'ず'}, 'せ': {'ぜ'}, 'そ': {'ぞ'},
+	'た': {'だ'}, 'ち': {'ぢ', 'じ'}, 'つ': {'づ', 'ず'}, 'て': {'で'}, 'と': {'ど'},
+	'は': {'ば', 'ぱ'}, 'ひ': {'び', 'ぴ'}, 'ふ': {'ぶ', 'ぷ'}, 'へ': {'べ', 'ぺ'}, 'ほ': {'ぼ', 'ぽ'},
+}
+
+// soundChanges returns a reading with its rendaku and gemination variants
+func soundChanges(reading string) []string {
+	if reading == "" {
+		return nil
+	}
+	variants := []string{reading}
+
+	runes := []rune(reading)
+	for _, voiced := range rendaku[runes[0]] {
+		variants = append(variants, string(voiced)+string(runes[1:]))
+	}
+
+	// Gemination: 日 にち → にっ (日本 にっぽん), 学 がく → がっ (学校 がっこう)
+	for _, v := range variants {
+		r := []rune(v)
+		if len(r) > 1 && strings.ContainsRune("つちくき", r[len(r)-1]) {
+			variants = append(variants, string(r[:len(r)-1])+"っ")
+		}
+	}
+
+	return variants
+}
+
+// segment is a run of kana or of kanji in the written form
+type segment struct {
+	text  []rune
+	kanji bool
+}
+
+// isKanji reports whether a character needs a reading above it
+func isKanji(r rune) bool {
+	return unicode.Is(unicode.Han, r) || r == iterationMark || r == 'ヶ' || r == 'ヵ'
+}
+
+// splitSegments splits a written form into kana and kanji runs
+func splitSegments(text []rune) []segment {
+	var segments []segment
+	for _, r := range text {
+		k := isKanji(r)
+		if n := len(segments); n > 0 && segments[n-1].kanji == k {
+			segments[n-1].text = append(segments[n-1].text, r)
+			continue
+		}
+		segments = append(segments, segment{text: []rune{r}, kanji: k})
+	}
+	return segments
+}
+
+// aligner holds the state of one alignment
+type aligner struct {
+	segments []segment
+	reading  []rune // Original reading, used for output
+	norm     []rune // Hiragana reading, used for matching
+	readings Readings
+	grouped  bool // Allow a kanji run to take a single group reading
+}
+
+// Align splits a kanji form and its kana reading into per-character ruby parts,
+// e.g. 日本/にほん → 日[に] 本[ほん] and 食べる/たべる → 食[た] べる.
+// Kanji runs that cannot be split per character (jukujikun such as 今日/きょう)
+// get a single group reading; if nothing aligns the whole word gets one ruby.
+func Align(text, reading string, readings Readings) []jmdict.FuriganaPart {
+	a := &aligner{
+		segments: splitSegments([]rune(text)),
+		reading:  []rune(reading),
+		norm:     []rune(romaji.ToHiragana(reading)),
+		readings: readings,
+	}
+
+	if parts, ok := a.solve(0, 0); ok {
+		return parts
+	}
+	a.grouped = true
+	if parts, ok := a.solve(0, 0); ok {
+		return parts
+	}
+	return []jmdict.FuriganaPart{{Base: text, Ruby: reading}}
+}
+
+// solve aligns segments[si:] with the reading from pos onwards
+func (a *aligner) solve(si, pos int) ([]jmdict.FuriganaPart, bool) {
+	if si == len(a.segments) {
+		return nil, pos == len(a.norm)
+	}
+	seg := a.segments[si]
+
+	if !seg.kanji {
+		kana := []rune(romaji.ToHiragana(string(seg.text)))
+		if pos+len(kana) > len(a.norm) || string(a.norm[pos:pos+len(kana)]) != string(kana) {
+			return nil, false
+		}
+		rest, ok := a.solve(si+1, pos+len(kana))
+		if !ok {
+			return nil, false
+		}
+		return append([]jmdict.FuriganaPart{{Base: string(seg.text)}}, rest...), true
+	}
+
+	// Prefer a per-character split at any span before falling back to a group reading
+	for end := len(a.norm); end > pos; end-- {
+		chars, ok := a.alignRun(seg.text, 0, pos, end, 0)
+		if !ok {
+			continue
+		}
+		if rest, ok := a.solve(si+1, end); ok {
+			return append(chars, rest...), true
+		}
+	}
+	if !a.grouped {
+		return nil, false
+	}
+	for end := len(a.norm); end > pos; end-- {
+		if rest, ok := a.solve(si+1, end); ok {
+			group := jmdict.FuriganaPart{Base: string(seg.text), Ruby: string(a.reading[pos:end])}
+			return append([]jmdict.FuriganaPart{group}, rest...), true
+		}
+	}
+	return nil, false
+}
+
+// alignRun gives each kanji in run[i:] one of its readings so that together
+// they cover norm[pos:end] exactly. prev is the previous kanji, for 々.
+func (a *aligner) alignRun(run []rune, i, pos, end int, prev rune) ([]jmdict.FuriganaPart, bool) {
+	if i == len(run) {
+		return nil, pos == end
+	}
+
+	char := run[i]
+	lookup := char
+	if char == iterationMark {
+		lookup = prev
+	}
+	candidates := a.readings[lookup]
+	if char == 'ヶ' || char == 'ヵ' {
+		candidates = []string{"か", "が", "こ"}
+	}
+
+	for _, c := range candidates {
+		n := len([]rune(c))
+		if pos+n > end || string(a.norm[pos:pos+n]) != c {
+			continue
+		}
+		rest, ok := a.alignRun(run, i+1, pos+n, end, lookup)
+		if !ok {
+			continue
+		}
+		part := jmdict.FuriganaPart{Base: string(char), Ruby: string(a.reading[pos : pos+n])}
+		return append([]jmdict.FuriganaPart{part}, rest...), true
+	}
+	return nil, false
+}
+
+// ForWord aligns every kanji form of a word with each kana reading that applies to it
+func ForWord(w jmdict.Word, readings Readings) []jmdict.Furigana {
+	var result []jmdict.Furigana
+	for _, k := range w.Kanji {
+		for _, kana := range w.Kana {
+			if !kana.AppliesTo(k.Text) {
+				continue
+			}
+			result = append(result, jmdict.Furigana{
+				Text:    k.Text,
+				Reading: kana.Text,
+				Parts:   Align(k.Text, kana.Text, readings),
+			})
+		}
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package furigana
+
+import (
+	"reflect"
+	"testing"
+
+	"kiokun-go/dictionaries/jmdict"
+)
+
+// testReadings is a small Kanjidic-style reading table
+func testReadings() Readings {
+	r := make(Readings)
+	r.Add('日', []string{"ニチ", "ジツ"}, []string{"ひ", "-び", "-か"}, []string{"に", "はる"})
+	r.Add('本', []string{"ホン"}, []string{"もと"}, nil)
+	r.Add('食', []string{"ショク", "ジキ"}, []string{"く.う", "く.らう", "た.べる", "は.む"}, nil)
+	r.Add('学', []string{"ガク"}, []string{"まな.ぶ"}, nil)
+	r.Add('校', []string{"コウ", "キョウ"}, nil, nil)
+	r.Add('人', []string{"ジン", "ニン"}, []string{"ひと", "-り", "-と"}, nil)
+	r.Add('今', []string{"コン", "キン"}, []string{"いま"}, nil)
+	r.Add('取', []string{"シュ"}, []string{"と.る", "と.り", "-ど.り"}, nil)
+	r.Add('引', []string{"イン"}, []string{"ひ.く", "ひ.け"}, nil)
+	return r
+}
+
+// parts builds furigana parts from alternating base/ruby pairs
+func parts(pairs ...string) []jmdict.FuriganaPart {
+	var result []jmdict.FuriganaPart
+	for i := 0; i < len(pairs); i += 2 {
+		result = append(result, jmdict.FuriganaPart{Base: pairs[i], Ruby: pairs[i+1]})
+	}
+	return result
+}
+
+func TestAlign(t *testing.T) {
+	readings := testReadings()
+
+	testCases := []struct {
+		text     string
+		reading  string
+		expected []jmdict.FuriganaPart
+	}{
+		{"日本", "にほん", parts("日", "に", "本", "ほん")},
+		{"日本", "にっぽん", parts("日", "にっ", "本", "ぽん")},
+		{"食べる", "たべる", parts("食", "た", "べる", "")},
+		{"学校", "がっこう", parts("学", "がっ", "校", "こう")},
+		{"人々", "ひとびと", parts("人", "ひと", "々", "びと")},
+		{"取引", "とりひき", parts("取", "とり", "引", "ひき")},
+		{"今日", "きょう", parts("今日", "きょう")},
+		{"今日は", "きょうは", parts("今日", "きょう", "は", "")},
+		{"日本", "ニホン", parts("日", "ニ", "本", "ホン")},
+	}
+
+	for _, tc := range testCases {
+		got := Align(tc.text, tc.reading, readings)
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Align(%s, %s) = %+v, want %+v", tc.text, tc.reading, got, tc.expected)
+		}
+	}
+}
+
+func TestAlignFallsBackToWholeWord(t *testing.T) {
+	got := Align("食べる", "のむ", testReadings())
+	expected := parts("食べる", "のむ")
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected whole-word ruby, got %+v", got)
+	}
+}
+
+func TestForWordRespectsKanaRestrictions(t *testing.T) {
+	word := jmdict.Word{
+		ID:    "1",
+		Kanji: []jmdict.KanjiEntry{{Text: "日本"}, {Text: "日本人"}},
+		Kana: []jmdict.KanaEntry{
+			{Text: "にほん", AppliesToKanji: []string{"日本"}},
+			{Text: "にっぽん", AppliesToKanji: []string{"*"}},
+		},
+	}
+
+	result := ForWord(word, testReadings())
+	var pairs []string
+	for _, f := range result {
+		pairs = append(pairs, f.Text+"/"+f.Reading)
+	}
+	expected := []string{"日本/にほん", "日本/にっぽん", "日本人/にっぽん"}
+	if !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("Unexpected pairs: %v, want %v", pairs, expected)
+	}
+}
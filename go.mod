@@ -4,6 +4,8 @@ go 1.22
 
 toolchain go1.22.1
 
+require golang.org/x/text v0.22.0
+
 require (
 	github.com/andybalholm/brotli v1.1.1 // indirect
 	github.com/klauspost/compress v1.18.0 // indirect
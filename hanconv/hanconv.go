@@ -0,0 +1,194 @@
+package hanconv
+
+import (
+	"sort"
+	"strings"
+
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/chinese_words"
+	"kiokun-go/dictionaries/common"
+)
+
+// Direction selects which script text is converted to
+type Direction int
+
+const (
+	ToTraditional Direction = iota
+	ToSimplified
+)
+
+// Ambiguity is a character that has more than one conversion in the dictionary,
+// such as simplified 发 (traditional 發 "emit" and 髮 "hair")
+type Ambiguity struct {
+	Offset  int      `json:"offset"`  // Byte offset of the character in the input
+	Source  string   `json:"source"`  // Character as written
+	Chosen  string   `json:"chosen"`  // Conversion used
+	Options []string `json:"options"` // Every conversion, most frequent first
+}
+
+// table is the conversion data for one direction
+type table struct {
+	phrases   map[string]string       // Multi-character words, converted as a whole
+	chars     map[rune][]string       // Single characters, most frequent conversion first
+	maxPhrase int                     // Length in characters of the longest phrase
+	votes     map[rune]map[string]int // Character pair counts, only while building
+}
+
+// Converter converts between traditional and simplified Chinese using the
+// Traditional/Simplified pairs of the Chinese word and character dictionaries
+type Converter struct {
+	tables [2]*table
+}
+
+// NewConverter builds a converter from ChineseWordEntry and ChineseCharEntry values;
+// other entries are ignored. Multi-character words become phrases converted as a
+// whole. Every aligned character pair, from words and from character entries, is a
+// vote for that character conversion; a character's options are ordered by votes.
+func NewConverter(entries []common.Entry) *Converter {
+	c := &Converter{}
+	for i := range c.tables {
+		c.tables[i] = &table{
+			phrases: make(map[string]string),
+			chars:   make(map[rune][]string),
+			votes:   make(map[rune]map[string]int),
+		}
+	}
+
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case chinese_words.ChineseWordEntry:
+			c.addPair(e.Traditional, e.Simplified)
+		case chinese_chars.ChineseCharEntry:
+			c.addChar(e)
+		}
+	}
+
+	for _, t := range c.tables {
+		t.finish()
+	}
+	return c
+}
+
+// addPair records one traditional/simplified pair in both directions
+func (c *Converter) addPair(traditional, simplified string) {
+	if traditional == "" || simplified == "" {
+		return
+	}
+	c.tables[ToTraditional].add(simplified, traditional)
+	c.tables[ToSimplified].add(traditional, simplified)
+}
+
+// addChar records the pairs of a character entry. The importer writes a simplified
+// character such as 发 as its own traditional form, listing 發 and 髮 in
+// TradVariants, so its traditional votes go to those variants instead.
+func (c *Converter) addChar(e chinese_chars.ChineseCharEntry) {
+	if e.Traditional != e.Simplified || len(e.TradVariants) == 0 {
+		c.addPair(e.Traditional, e.Simplified)
+		return
+	}
+	c.tables[ToSimplified].add(e.Simplified, e.Simplified)
+	for _, traditional := range e.TradVariants {
+		if traditional != "" {
+			c.tables[ToTraditional].add(e.Simplified, traditional)
+		}
+	}
+}
+
+// add records one source → target pair
+func (t *table) add(source, target string) {
+	src, dst := []rune(source), []rune(target)
+
+	if len(src) > 1 {
+		// The first pair seen for a phrase wins; words are imported in dictionary order
+		if _, ok := t.phrases[source]; !ok {
+			t.phrases[source] = target
+		}
+		if len(src) > t.maxPhrase {
+			t.maxPhrase = len(src)
+		}
+	}
+
+	// Character votes only come from pairs that align one to one
+	if len(src) != len(dst) {
+		return
+	}
+	for i, r := range src {
+		if t.votes[r] == nil {
+			t.votes[r] = make(map[string]int)
+		}
+		t.votes[r][string(dst[i])]++
+	}
+}
+
+// finish orders each character's conversions by votes
+func (t *table) finish() {
+	for r, votes := range t.votes {
+		options := make([]string, 0, len(votes))
+		for target := range votes {
+			options = append(options, target)
+		}
+		sort.Slice(options, func(i, j int) bool {
+			if votes[options[i]] != votes[options[j]] {
+				return votes[options[i]] > votes[options[j]]
+			}
+			return options[i] < options[j]
+		})
+		t.chars[r] = options
+	}
+	t.votes = nil
+}
+
+// Convert converts text by longest match against the dictionary's words, falling back
+// to single characters. Characters converted on their own that have more than one
+// conversion are reported as ambiguities; characters inside a matched word are not,
+// since the word settles them. Text with no conversion is copied unchanged.
+func (c *Converter) Convert(text string, dir Direction) (string, []Ambiguity) {
+	t := c.tables[dir]
+	runes := []rune(text)
+
+	var b strings.Builder
+	var ambiguities []Ambiguity
+	offset := 0
+
+	for i := 0; i < len(runes); {
+		matched := false
+		for end := min(len(runes), i+t.maxPhrase); end > i+1; end-- {
+			phrase := string(runes[i:end])
+			if target, ok := t.phrases[phrase]; ok {
+				b.WriteString(target)
+				offset += len(phrase)
+				i = end
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		r := runes[i]
+		options := t.chars[r]
+		if len(options) == 0 {
+			b.WriteRune(r)
+		} else {
+			b.WriteString(options[0])
+		}
+		if len(options) > 1 {
+			ambiguities = append(ambiguities, Ambiguity{
+				Offset:  offset,
+				Source:  string(r),
+				Chosen:  options[0],
+				Options: options,
+			})
+		}
+		offset += len(string(r))
+		i++
+	}
+
+	return b.String(), ambiguities
+}
+
+// Options returns every conversion of a single character, most frequent first
+func (c *Converter) Options(char rune, dir Direction) []string {
+	return c.tables[dir].chars[char]
+}
@@ -0,0 +1,89 @@
+package hanconv
+
+import (
+	"reflect"
+	"testing"
+
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/chinese_words"
+	"kiokun-go/dictionaries/common"
+)
+
+// testConverter builds a converter from entries shaped like importer output:
+// simplified characters are their own traditional form and list their traditional
+// variants, traditional characters list their simplified form
+func testConverter() *Converter {
+	return NewConverter([]common.Entry{
+		chinese_words.ChineseWordEntry{ID: "1", Traditional: "頭髮", Simplified: "头发"},
+		chinese_words.ChineseWordEntry{ID: "2", Traditional: "發展", Simplified: "发展"},
+		chinese_words.ChineseWordEntry{ID: "3", Traditional: "發現", Simplified: "发现"},
+		chinese_words.ChineseWordEntry{ID: "4", Traditional: "中國", Simplified: "中国"},
+		chinese_words.ChineseWordEntry{ID: "5", Traditional: "乾杯", Simplified: "干杯"},
+		chinese_chars.ChineseCharEntry{ID: "6", Traditional: "发", Simplified: "发", TradVariants: []string{"發", "髮"}},
+		chinese_chars.ChineseCharEntry{ID: "7", Traditional: "髮", Simplified: "发", SimpVariants: []string{"发"}},
+		chinese_chars.ChineseCharEntry{ID: "8", Traditional: "發", Simplified: "发", SimpVariants: []string{"发"}},
+		chinese_chars.ChineseCharEntry{ID: "9", Traditional: "国", Simplified: "国", TradVariants: []string{"國"}},
+		chinese_chars.ChineseCharEntry{ID: "10", Traditional: "國", Simplified: "国", SimpVariants: []string{"国"}},
+		chinese_chars.ChineseCharEntry{ID: "11", Traditional: "学", Simplified: "学", TradVariants: []string{"學"}},
+		chinese_chars.ChineseCharEntry{ID: "12", Traditional: "學", Simplified: "学", SimpVariants: []string{"学"}},
+		chinese_chars.ChineseCharEntry{ID: "13", Traditional: "我", Simplified: "我"},
+	})
+}
+
+func TestConvertToTraditional(t *testing.T) {
+	c := testConverter()
+
+	testCases := []struct {
+		text        string
+		expected    string
+		ambiguities []Ambiguity
+	}{
+		{"头发", "頭髮", nil},
+		{"发展中国", "發展中國", nil},
+		{"干杯", "乾杯", nil},
+		{"学国", "學國", nil},
+		{"我发", "我發", []Ambiguity{{Offset: 3, Source: "发", Chosen: "發", Options: []string{"發", "髮"}}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			got, ambiguities := c.Convert(tc.text, ToTraditional)
+			if got != tc.expected {
+				t.Errorf("Convert(%s) = %s, want %s", tc.text, got, tc.expected)
+			}
+			if !reflect.DeepEqual(ambiguities, tc.ambiguities) {
+				t.Errorf("Ambiguities = %+v, want %+v", ambiguities, tc.ambiguities)
+			}
+		})
+	}
+}
+
+func TestConvertCharactersOnly(t *testing.T) {
+	// A simplified character seen in no word still converts through its variants
+	c := NewConverter([]common.Entry{
+		chinese_chars.ChineseCharEntry{ID: "1", Traditional: "发", Simplified: "发", TradVariants: []string{"發", "髮"}},
+		chinese_chars.ChineseCharEntry{ID: "2", Traditional: "發", Simplified: "发", SimpVariants: []string{"发"}},
+		chinese_chars.ChineseCharEntry{ID: "3", Traditional: "髮", Simplified: "发", SimpVariants: []string{"发"}},
+	})
+
+	got, ambiguities := c.Convert("发", ToTraditional)
+	want := []Ambiguity{{Offset: 0, Source: "发", Chosen: "發", Options: []string{"發", "髮"}}}
+	if got != "發" || !reflect.DeepEqual(ambiguities, want) {
+		t.Errorf("Convert(发) = %s %+v, want 發 %+v", got, ambiguities, want)
+	}
+	if got, _ := c.Convert("发", ToSimplified); got != "发" {
+		t.Errorf("Convert(发) to simplified = %s", got)
+	}
+}
+
+func TestConvertToSimplified(t *testing.T) {
+	c := testConverter()
+
+	got, ambiguities := c.Convert("頭髮發展，中國學", ToSimplified)
+	if got != "头发发展，中国学" {
+		t.Errorf("Convert = %s", got)
+	}
+	if len(ambiguities) != 0 {
+		t.Errorf("Expected no ambiguities, got %+v", ambiguities)
+	}
+}
@@ -0,0 +1,99 @@
+package lookup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"kiokun-go/dictionaries/kradfile"
+	"kiokun-go/processor"
+)
+
+// Browse returns the sharded Kanjidic IDs listed under a key of a browse index
+// (processor.BrowseSKIP, BrowseFourCorner or BrowseRadical), most frequent first.
+// Load the entries with Entry("d", id). It returns nil when no shard has the key.
+func (d *Dictionary) Browse(index, key string) ([]int64, error) {
+	var result []int64
+	seen := make(map[int64]bool)
+	for _, shardType := range allShards {
+		path := filepath.Join(d.shardDir(shardType), "browse", index, processor.BrowseFilename(key))
+		var ids []int64
+		if err := readCompressedJSON(path, &ids); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("error reading browse file %s: %v", path, err)
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				result = append(result, id)
+			}
+		}
+	}
+	return result, nil
+}
+
+// BrowseSKIP returns the kanji with a SKIP code such as 1-4-4, including kanji
+// commonly misclassified under it
+func (d *Dictionary) BrowseSKIP(code string) ([]int64, error) {
+	return d.Browse(processor.BrowseSKIP, code)
+}
+
+// BrowseFourCorner returns the kanji with a four-corner code such as 6010.0
+func (d *Dictionary) BrowseFourCorner(code string) ([]int64, error) {
+	return d.Browse(processor.BrowseFourCorner, code)
+}
+
+// BrowseRadical returns the kanji under a classical radical number with a number of
+// strokes besides the radical, like the radical index of a paper dictionary: 85 and
+// 6 give 海. Radicals with a reduced form (氵 for 水) count the strokes after either.
+func (d *Dictionary) BrowseRadical(radical, remaining int) ([]int64, error) {
+	return d.Browse(processor.BrowseRadical, processor.RadicalKey(radical, remaining))
+}
+
+// Radicals returns the KRADFILE radical picker table: every lookup radical with its
+// stroke count and the kanji containing it, fewest strokes first. It returns nil
+// when the dictionary was built without KRADFILE data.
+func (d *Dictionary) Radicals() ([]kradfile.Radical, error) {
+	path := filepath.Join(d.shardDir(processor.ShardHan1Char), "browse", processor.RadicalsFilename)
+	var radicals []kradfile.Radical
+	if err := readCompressedJSON(path, &radicals); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading radical table %s: %v", path, err)
+	}
+	return radicals, nil
+}
+
+// SearchRadicals returns the kanji containing every one of the given KRADFILE
+// radicals, in the ranked order of the radical table, like a multi-radical picker
+func (d *Dictionary) SearchRadicals(radicals ...string) ([]string, error) {
+	if len(radicals) == 0 {
+		return nil, nil
+	}
+	table, err := d.Radicals()
+	if err != nil {
+		return nil, err
+	}
+
+	lists := make(map[string][]string, len(table))
+	for _, r := range table {
+		lists[r.Radical] = r.Kanji
+	}
+
+	counts := make(map[string]int)
+	for _, radical := range radicals {
+		for _, kanji := range lists[radical] {
+			counts[kanji]++
+		}
+	}
+	var result []string
+	for _, kanji := range lists[radicals[0]] {
+		if counts[kanji] == len(radicals) {
+			result = append(result, kanji)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package lookup
+
+// SearchComponents returns the characters containing every one of the given
+// components (海 for 氵 and 每), fewest strokes first, by intersecting the
+// component links of their index entries. It returns nil when any component has
+// no links.
+func (d *Dictionary) SearchComponents(components ...string) ([]string, error) {
+	var result []string
+	for i, component := range components {
+		entry, err := d.Index(component)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil || len(entry.K) == 0 {
+			return nil, nil
+		}
+
+		if i == 0 {
+			result = entry.K
+			continue
+		}
+
+		// Keep the ranked order of the first list
+		contains := make(map[string]bool, len(entry.K))
+		for _, char := range entry.K {
+			contains[char] = true
+		}
+		var kept []string
+		for _, char := range result {
+			if contains[char] {
+				kept = append(kept, char)
+			}
+		}
+		if len(kept) == 0 {
+			return nil, nil
+		}
+		result = kept
+	}
+	return result, nil
+}
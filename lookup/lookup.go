@@ -0,0 +1,401 @@
+package lookup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/chinese_words"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/jmdict"
+	"kiokun-go/dictionaries/jmnedict"
+	"kiokun-go/dictionaries/kanjidic"
+	"kiokun-go/dictionaries/kanjivg"
+	"kiokun-go/dictionaries/makemeahanzi"
+	"kiokun-go/pinyin"
+	"kiokun-go/processor"
+	"kiokun-go/romaji"
+
+	"github.com/andybalholm/brotli"
+)
+
+// allShards lists every shard in the order they are searched
+var allShards = []processor.ShardType{
+	processor.ShardHan1Char,
+	processor.ShardHan2Char,
+	processor.ShardHan3Plus,
+	processor.ShardNonHan,
+}
+
+// latinLetters are the letters ToKana leaves behind when input is not valid romaji
+const latinLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Dictionary reads the sharded output written by processor.ShardedIndexProcessor
+type Dictionary struct {
+	baseDir string
+}
+
+// Open returns a Dictionary for the shard directories derived from baseDir
+// (baseDir_non_han, baseDir_han_1char, ...). At least one shard must exist.
+func Open(baseDir string) (*Dictionary, error) {
+	d := &Dictionary{baseDir: baseDir}
+	for _, shardType := range allShards {
+		if _, err := os.Stat(d.shardDir(shardType)); err == nil {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("no dictionary shards found for %s", baseDir)
+}
+
+// shardDir returns the output directory of a shard
+func (d *Dictionary) shardDir(shardType processor.ShardType) string {
+	return processor.GetOutputDirForShard(d.baseDir, shardType)
+}
+
+// Index reads the index entry for a key, merging the files from every shard.
+// It returns nil when no shard has the key.
+func (d *Dictionary) Index(key string) (*processor.IndexEntry, error) {
+	var merged *processor.IndexEntry
+	for _, shardType := range allShards {
+		entry, err := d.readIndex(shardType, key)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &processor.IndexEntry{}
+		}
+		mergeIndexEntry(merged, entry)
+	}
+	return merged, nil
+}
+
+// readIndex reads a single shard's index file for a key, in either the plain or the
+// compact format. A missing file is not an error; it returns nil.
+func (d *Dictionary) readIndex(shardType processor.ShardType, key string) (*processor.IndexEntry, error) {
+	path := filepath.Join(d.shardDir(shardType), "index", key+".json.br")
+	var data json.RawMessage
+	if err := readCompressedJSON(path, &data); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading index %s: %v", path, err)
+	}
+	entry, err := decodeIndexEntry(data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding index %s: %v", path, err)
+	}
+	return entry, nil
+}
+
+// Lookup looks up a query and returns the merged index entry, or nil if nothing matched.
+// Romaji queries (taberu, toukyou, tōkyō) are matched against the romaji keys in the
+// non-Han shard and against the kana spelling they convert to. Pinyin queries (riben,
+// ri4ben3, rì běn) are normalized to the pinyin key of the same form. Alias links are
+// followed through the canonical key, and the exact matches of variant keys (國 for
+// 国) are returned in X.
+func (d *Dictionary) Lookup(query string) (*processor.IndexEntry, error) {
+	result, err := d.Index(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if result != nil {
+		// Variant keys link to their canonical key, which links back to the other
+		// variant keys, so the links are followed two hops deep
+		visited := map[string]bool{query: true}
+		aliases := result.A
+		for depth := 0; depth < 2 && len(aliases) > 0; depth++ {
+			var next []string
+			for _, alias := range aliases {
+				if visited[alias] {
+					continue
+				}
+				visited[alias] = true
+				entry, err := d.Index(alias)
+				if err != nil {
+					return nil, err
+				}
+				if entry != nil {
+					result.X = mergePostings(result.X, entry.E)
+					next = append(next, entry.A...)
+				}
+			}
+			aliases = next
+		}
+		// Entries the query already matches exactly are not variant matches
+		for dictType, ids := range result.X {
+			exact := idSet(result.E[dictType])
+			var kept []int64
+			for _, id := range ids {
+				if !exact[id] {
+					kept = append(kept, id)
+				}
+			}
+			if len(kept) == 0 {
+				delete(result.X, dictType)
+			} else {
+				result.X[dictType] = kept
+			}
+		}
+		if len(result.X) == 0 {
+			result.X = nil
+		}
+	}
+
+	add := func(entry *processor.IndexEntry) {
+		if entry == nil {
+			return
+		}
+		if result == nil {
+			result = &processor.IndexEntry{}
+		}
+		mergeIndexEntry(result, entry)
+	}
+
+	// Pinyin keys map back to Chinese entries through the Y section
+	if key, ok := pinyin.SearchKey(query); ok && key != query {
+		entry, err := d.readIndex(processor.ShardNonHan, key)
+		if err != nil {
+			return nil, err
+		}
+		add(entry)
+	}
+
+	if !romaji.IsRomaji(query) {
+		return result, nil
+	}
+
+	// Romaji keys map back to the original entries through the R section
+	if key := romaji.Normalize(query); key != "" && key != query {
+		entry, err := d.readIndex(processor.ShardNonHan, key)
+		if err != nil {
+			return nil, err
+		}
+		add(entry)
+	}
+
+	// The kana spelling finds the reading's own exact matches
+	if kana := romaji.ToKana(query); !strings.ContainsAny(kana, latinLetters) {
+		entry, err := d.Index(kana)
+		if err != nil {
+			return nil, err
+		}
+		add(entry)
+	}
+
+	return result, nil
+}
+
+// Entry loads a dictionary entry by dictionary type code (j, n, d, c, w) and sharded ID
+func (d *Dictionary) Entry(dictType string, id int64) (common.Entry, error) {
+	path, err := d.entryPath(dictType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch dictType {
+	case "j":
+		var e jmdict.Word
+		err = readCompressedJSON(path, &e)
+		return e, err
+	case "n":
+		var e jmnedict.Name
+		err = readCompressedJSON(path, &e)
+		return e, err
+	case "d":
+		var e kanjidic.Kanji
+		err = readCompressedJSON(path, &e)
+		return e, err
+	case "c":
+		var e chinese_chars.ChineseCharEntry
+		err = readCompressedJSON(path, &e)
+		return e, err
+	case "w":
+		var e chinese_words.ChineseWordEntry
+		err = readCompressedJSON(path, &e)
+		return e, err
+	default:
+		return nil, fmt.Errorf("unknown dictionary type: %s", dictType)
+	}
+}
+
+// Strokes loads the KanjiVG stroke order written next to a Kanjidic (d) or Chinese
+// character (c) entry. It returns nil when the character has no stroke data.
+func (d *Dictionary) Strokes(dictType string, id int64) (*kanjivg.Character, error) {
+	var strokes kanjivg.Character
+	if ok, err := d.readCompanion(dictType, id, processor.StrokesSuffix, &strokes); !ok {
+		return nil, err
+	}
+	return &strokes, nil
+}
+
+// Graphics loads the Make Me a Hanzi stroke graphics written next to a Chinese
+// character entry. It returns nil when the character has no graphics.
+func (d *Dictionary) Graphics(id int64) (*makemeahanzi.Graphics, error) {
+	var graphics makemeahanzi.Graphics
+	if ok, err := d.readCompanion("c", id, processor.GraphicsSuffix, &graphics); !ok {
+		return nil, err
+	}
+	return &graphics, nil
+}
+
+// readCompanion reads the companion file with the given suffix written next to an
+// entry, reporting false with no error when the entry has none
+func (d *Dictionary) readCompanion(dictType string, id int64, suffix string, v interface{}) (bool, error) {
+	path, err := d.entryPath(dictType, id)
+	if err != nil {
+		return false, err
+	}
+
+	path = strings.TrimSuffix(path, ".json.br") + suffix
+	if err := readCompressedJSON(path, v); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	return true, nil
+}
+
+// entryPath finds the file of an entry. Non-Han sharded IDs start with the
+// shard digit 0, which is lost in the int64 form, so they are tried second.
+func (d *Dictionary) entryPath(dictType string, id int64) (string, error) {
+	shardedID := strconv.FormatInt(id, 10)
+	if shardType, err := processor.ExtractShardType(shardedID); err == nil {
+		path := filepath.Join(d.shardDir(shardType), dictType, shardedID+".json.br")
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	path := filepath.Join(d.shardDir(processor.ShardNonHan), dictType, "0"+shardedID+".json.br")
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("entry %s/%d not found", dictType, id)
+	}
+	return path, nil
+}
+
+// mergeIndexEntry adds every ID in src to dst, skipping duplicates
+func mergeIndexEntry(dst, src *processor.IndexEntry) {
+	mergeExact(dst, src)
+	mergeContained(dst, src)
+	dst.G = mergePostings(dst.G, src.G)
+	dst.R = mergePostings(dst.R, src.R)
+	dst.Y = mergePostings(dst.Y, src.Y)
+	dst.X = mergePostings(dst.X, src.X)
+	for _, alias := range src.A {
+		if !containsKey(dst.A, alias) {
+			dst.A = append(dst.A, alias)
+		}
+	}
+	for _, char := range src.K {
+		if !containsKey(dst.K, char) {
+			dst.K = append(dst.K, char)
+		}
+	}
+}
+
+// containsKey reports whether a list of keys contains a key
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeExact merges exact matches, keeping matched forms parallel to them
+func mergeExact(dst, src *processor.IndexEntry) {
+	for dictType, ids := range src.E {
+		if dst.E == nil {
+			dst.E = make(map[string][]int64)
+		}
+		seen := idSet(dst.E[dictType])
+		forms := src.F[dictType]
+		for i, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			dst.E[dictType] = append(dst.E[dictType], id)
+			if i < len(forms) {
+				if dst.F == nil {
+					dst.F = make(map[string][]int)
+				}
+				dst.F[dictType] = append(dst.F[dictType], forms[i])
+			}
+		}
+	}
+}
+
+// mergeContained merges contained-in matches, keeping positions parallel to them
+func mergeContained(dst, src *processor.IndexEntry) {
+	for dictType, ids := range src.C {
+		if dst.C == nil {
+			dst.C = make(map[string][]int64)
+		}
+		seen := idSet(dst.C[dictType])
+		positions := src.P[dictType]
+		for i, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			dst.C[dictType] = append(dst.C[dictType], id)
+			if i < len(positions) {
+				if dst.P == nil {
+					dst.P = make(map[string][]processor.Position)
+				}
+				dst.P[dictType] = append(dst.P[dictType], positions[i])
+			}
+		}
+	}
+}
+
+// idSet returns the IDs of a posting list as a set
+func idSet(ids []int64) map[int64]bool {
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// mergePostings merges posting lists by dictionary type
+func mergePostings(dst, src map[string][]int64) map[string][]int64 {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string][]int64)
+	}
+	for dictType, ids := range src {
+		seen := idSet(dst[dictType])
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				dst[dictType] = append(dst[dictType], id)
+			}
+		}
+	}
+	return dst
+}
+
+// readCompressedJSON decodes a Brotli-compressed JSON file
+func readCompressedJSON(path string, v interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewDecoder(brotli.NewReader(file)).Decode(v)
+}
@@ -0,0 +1,851 @@
+package lookup
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"kiokun-go/cognates"
+	"kiokun-go/components"
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/chinese_words"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/ids"
+	"kiokun-go/dictionaries/jmdict"
+	"kiokun-go/dictionaries/kanjidic"
+	"kiokun-go/dictionaries/kanjivg"
+	"kiokun-go/dictionaries/kradfile"
+	"kiokun-go/dictionaries/makemeahanzi"
+	"kiokun-go/processor"
+	"kiokun-go/variants"
+)
+
+// buildTestDictionary writes a small sharded dictionary into a temp directory
+func buildTestDictionary(t *testing.T, entries []common.Entry) *Dictionary {
+	t.Helper()
+	return buildTestDictionaryWith(t, entries, nil)
+}
+
+// buildTestDictionaryWith writes a small sharded dictionary, letting configure
+// change the processor's settings before entries are processed
+func buildTestDictionaryWith(t *testing.T, entries []common.Entry, configure func(*processor.ShardedIndexProcessor)) *Dictionary {
+	t.Helper()
+
+	baseDir := filepath.Join(t.TempDir(), "dict")
+	p, err := processor.NewShardedIndexProcessor(baseDir, 1)
+	if err != nil {
+		t.Fatalf("Error creating processor: %v", err)
+	}
+	graph := variants.FromEntries(entries)
+	p.SetVariants(graph)
+	p.SetCognates(cognates.Find(entries, entries, graph))
+	if configure != nil {
+		configure(p)
+	}
+	if err := p.ProcessEntries(entries); err != nil {
+		t.Fatalf("Error processing entries: %v", err)
+	}
+	if err := p.WriteToFiles(); err != nil {
+		t.Fatalf("Error writing files: %v", err)
+	}
+
+	d, err := Open(baseDir)
+	if err != nil {
+		t.Fatalf("Error opening dictionary: %v", err)
+	}
+	return d
+}
+
+func TestRomajiLookup(t *testing.T) {
+	d := buildTestDictionary(t, []common.Entry{
+		jmdict.Word{
+			ID:    "1358280",
+			Kanji: []jmdict.KanjiEntry{{Text: "食べる"}},
+			Kana:  []jmdict.KanaEntry{{Text: "たべる"}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"v1"}}},
+		},
+		jmdict.Word{
+			ID:    "1439430",
+			Kanji: []jmdict.KanjiEntry{{Text: "東京"}},
+			Kana:  []jmdict.KanaEntry{{Text: "とうきょう"}},
+		},
+	})
+
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{"taberu", "食べる"},
+		{"toukyou", "東京"},
+		{"tōkyō", "東京"},
+		{"tokyo", "東京"},
+		{"Tokyo", "東京"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			result, err := d.Lookup(tc.query)
+			if err != nil {
+				t.Fatalf("Lookup error: %v", err)
+			}
+			if result == nil {
+				t.Fatalf("Expected a result for %s", tc.query)
+			}
+
+			ids := append(append([]int64{}, result.E["j"]...), result.R["j"]...)
+			found := false
+			for _, id := range ids {
+				entry, err := d.Entry("j", id)
+				if err != nil {
+					t.Fatalf("Error loading entry %d: %v", id, err)
+				}
+				if word := entry.(jmdict.Word); word.Kanji[0].Text == tc.expected {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected %s to find %s, got %+v", tc.query, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestLookupMissingKey(t *testing.T) {
+	d := buildTestDictionary(t, []common.Entry{
+		jmdict.Word{ID: "1358280", Kana: []jmdict.KanaEntry{{Text: "たべる"}}},
+	})
+
+	result, err := d.Lookup("nomu")
+	if err != nil {
+		t.Fatalf("Lookup error: %v", err)
+	}
+	if result != nil {
+		t.Errorf("Expected no result, got %+v", result)
+	}
+}
+
+func TestPinyinLookup(t *testing.T) {
+	d := buildTestDictionary(t, []common.Entry{
+		chinese_words.ChineseWordEntry{
+			ID:          "4000001",
+			Traditional: "日本",
+			Simplified:  "日本",
+			Pinyin:      []string{"rì běn"},
+		},
+		chinese_words.ChineseWordEntry{
+			ID:          "4000002",
+			Traditional: "銀行",
+			Simplified:  "银行",
+			Items:       []chinese_words.Item{{Source: "cedict", Pinyin: "yín háng", Definitions: []string{"bank"}}},
+		},
+		chinese_chars.ChineseCharEntry{
+			ID:          "3000001",
+			Traditional: "綠",
+			Simplified:  "绿",
+			Pinyin:      []string{"lǜ"},
+		},
+	})
+
+	testCases := []struct {
+		query    string
+		dictType string
+		expected string
+	}{
+		{"riben", "w", "日本"},
+		{"ri4ben3", "w", "日本"},
+		{"rìběn", "w", "日本"},
+		{"Rì běn", "w", "日本"},
+		{"ri4 ben3", "w", "日本"},
+		{"yin2hang2", "w", "銀行"}, // Only in the word's items
+		{"lv4", "c", "綠"},
+		{"lu:4", "c", "綠"},
+		{"lü", "c", "綠"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			result, err := d.Lookup(tc.query)
+			if err != nil {
+				t.Fatalf("Lookup error: %v", err)
+			}
+			if result == nil || len(result.Y[tc.dictType]) == 0 {
+				t.Fatalf("Expected pinyin matches for %s, got %+v", tc.query, result)
+			}
+
+			entry, err := d.Entry(tc.dictType, result.Y[tc.dictType][0])
+			if err != nil {
+				t.Fatalf("Error loading entry: %v", err)
+			}
+			var traditional string
+			switch e := entry.(type) {
+			case chinese_words.ChineseWordEntry:
+				traditional = e.Traditional
+			case chinese_chars.ChineseCharEntry:
+				traditional = e.Traditional
+			}
+			if traditional != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, traditional)
+			}
+		})
+	}
+}
+
+func TestMatchedFormSenses(t *testing.T) {
+	d := buildTestDictionary(t, []common.Entry{
+		jmdict.Word{
+			ID:    "1234560",
+			Kanji: []jmdict.KanjiEntry{{Text: "空"}, {Text: "虚"}},
+			Kana: []jmdict.KanaEntry{
+				{Text: "そら", AppliesToKanji: []string{"空"}},
+				{Text: "から", AppliesToKanji: []string{"*"}},
+			},
+			Sense: []jmdict.Sense{
+				{AppliesToKanji: []string{"空"}, AppliesToKana: []string{"そら"}, Gloss: []jmdict.Gloss{{Text: "sky"}}},
+				{AppliesToKana: []string{"から"}, Gloss: []jmdict.Gloss{{Text: "empty"}}},
+				{AppliesToKanji: []string{"虚"}, Gloss: []jmdict.Gloss{{Text: "void"}}},
+			},
+		},
+	})
+
+	testCases := []struct {
+		query    string
+		readings []string
+		glosses  []string
+	}{
+		{"空", []string{"そら", "から"}, []string{"sky", "empty"}},
+		{"虚", []string{"から"}, []string{"empty", "void"}},
+		{"そら", []string{"そら"}, []string{"sky"}},
+		{"から", []string{"から"}, []string{"empty", "void"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			result, err := d.Lookup(tc.query)
+			if err != nil {
+				t.Fatalf("Lookup error: %v", err)
+			}
+			if result == nil || len(result.E["j"]) != 1 {
+				t.Fatalf("Expected one exact match for %s, got %+v", tc.query, result)
+			}
+
+			id := result.E["j"][0]
+			formIndex, ok := result.MatchedForm("j", id)
+			if !ok {
+				t.Fatalf("Expected a matched form for %s", tc.query)
+			}
+			entry, err := d.Entry("j", id)
+			if err != nil {
+				t.Fatalf("Error loading entry %d: %v", id, err)
+			}
+			word := entry.(jmdict.Word)
+			form := word.Forms[formIndex]
+			if form.Text != tc.query {
+				t.Fatalf("Expected form %s, got %s", tc.query, form.Text)
+			}
+
+			var readings []string
+			for _, r := range form.Readings {
+				readings = append(readings, r.Text)
+			}
+			if strings.Join(readings, ",") != strings.Join(tc.readings, ",") {
+				t.Errorf("Expected readings %v, got %v", tc.readings, readings)
+			}
+
+			var glosses []string
+			for _, s := range word.SensesForForm(form.Text) {
+				glosses = append(glosses, s.Gloss[0].Text)
+			}
+			if strings.Join(glosses, ",") != strings.Join(tc.glosses, ",") {
+				t.Errorf("Expected senses %v, got %v", tc.glosses, glosses)
+			}
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	d := buildTestDictionary(t, []common.Entry{
+		jmdict.Word{
+			ID:    "1464530",
+			Kanji: []jmdict.KanjiEntry{{Text: "日本"}},
+			Kana:  []jmdict.KanaEntry{{Text: "にほん"}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"n"}}},
+		},
+		jmdict.Word{
+			ID:    "1464560",
+			Kanji: []jmdict.KanjiEntry{{Text: "日本語"}},
+			Kana:  []jmdict.KanaEntry{{Text: "にほんご"}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"n"}}},
+		},
+		jmdict.Word{
+			ID:    "1403570",
+			Kanji: []jmdict.KanjiEntry{{Text: "勉強"}},
+			Kana:  []jmdict.KanaEntry{{Text: "べんきょう"}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"n", "vs"}}},
+		},
+		jmdict.Word{
+			ID:    "1358280",
+			Kanji: []jmdict.KanjiEntry{{Text: "食べる"}},
+			Kana:  []jmdict.KanaEntry{{Text: "たべる"}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"v1"}}},
+		},
+		jmdict.Word{
+			ID:    "2029010",
+			Kana:  []jmdict.KanaEntry{{Text: "を"}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"prt"}}},
+		},
+		chinese_words.ChineseWordEntry{
+			ID:          "30001",
+			Traditional: "中文",
+			Simplified:  "中文",
+			Pinyin:      []string{"zhōng wén"},
+		},
+	})
+
+	testCases := []struct {
+		text     string
+		expected []string // Token text, then "=base" when the match was deinflected
+	}{
+		{"日本語を勉強しました。", []string{"日本語", "を", "勉強しました=勉強", "。"}},
+		{"食べなかった", []string{"食べなかった=食べる"}},
+		{"我学中文", []string{"我", "学", "中文"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			tokens, err := d.Scan(tc.text)
+			if err != nil {
+				t.Fatalf("Scan error: %v", err)
+			}
+
+			var got []string
+			rebuilt := ""
+			for _, token := range tokens {
+				rebuilt += tc.text[token.Start:token.End]
+				s := token.Text
+				if token.Base != "" && token.Base != token.Text {
+					s += "=" + token.Base
+				}
+				got = append(got, s)
+			}
+			if strings.Join(got, "|") != strings.Join(tc.expected, "|") {
+				t.Errorf("Scan(%s) = %v, want %v", tc.text, got, tc.expected)
+			}
+			if rebuilt != tc.text {
+				t.Errorf("Token offsets rebuild %q, want %q", rebuilt, tc.text)
+			}
+		})
+	}
+}
+
+func TestVariantLookup(t *testing.T) {
+	d := buildTestDictionary(t, []common.Entry{
+		jmdict.Word{
+			ID:    "1000010",
+			Kanji: []jmdict.KanjiEntry{{Text: "國"}},
+			Kana:  []jmdict.KanaEntry{{Text: "くに"}},
+		},
+		chinese_chars.ChineseCharEntry{ID: "3000001", Traditional: "鐵", Simplified: "铁"},
+	})
+
+	testCases := []struct {
+		query    string
+		dictType string
+		exact    int
+		variant  int
+	}{
+		{"国", "j", 0, 1},
+		{"鉄", "c", 0, 1},
+		{"铁", "c", 1, 0}, // Simplified forms are exact matches, not variants
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			result, err := d.Lookup(tc.query)
+			if err != nil {
+				t.Fatalf("Lookup error: %v", err)
+			}
+			if result == nil {
+				t.Fatalf("Expected a result for %s", tc.query)
+			}
+			if len(result.E[tc.dictType]) != tc.exact || len(result.X[tc.dictType]) != tc.variant {
+				t.Errorf("Expected %d exact and %d variant matches for %s, got %+v", tc.exact, tc.variant, tc.query, result)
+			}
+		})
+	}
+}
+
+func TestVariantWordLookup(t *testing.T) {
+	d := buildTestDictionary(t, []common.Entry{
+		jmdict.Word{
+			ID:    "1000020",
+			Kanji: []jmdict.KanjiEntry{{Text: "國家"}},
+			Kana:  []jmdict.KanaEntry{{Text: "こっか"}},
+		},
+		chinese_words.ChineseWordEntry{ID: "4000001", Traditional: "國家", Simplified: "国家"},
+		jmdict.Word{
+			ID:    "1000030",
+			Kanji: []jmdict.KanjiEntry{{Text: "鐵道"}},
+			Kana:  []jmdict.KanaEntry{{Text: "てつどう"}},
+		},
+		jmdict.Word{
+			ID:    "1000031",
+			Kanji: []jmdict.KanjiEntry{{Text: "铁道"}},
+			Kana:  []jmdict.KanaEntry{{Text: "てつどう"}},
+		},
+		chinese_chars.ChineseCharEntry{ID: "3000002", Traditional: "鐵", Simplified: "铁"},
+	})
+
+	testCases := []struct {
+		query    string
+		dictType string
+		exact    int
+		variant  int
+	}{
+		{"国家", "w", 1, 0}, // Simplified forms are exact matches, not variants
+		{"国家", "j", 0, 1}, // The canonical spelling links to 國家
+		{"國家", "j", 1, 0},
+		{"铁道", "j", 1, 1}, // 铁道 → 鉄道 → 鐵道
+		{"鉄道", "j", 0, 2},
+	}
+
+	// Variant keys link only to their canonical key
+	entry, err := d.Index("鐵道")
+	if err != nil {
+		t.Fatalf("Index error: %v", err)
+	}
+	if entry == nil || !reflect.DeepEqual(entry.A, []string{"鉄道"}) {
+		t.Errorf("Expected 鐵道 to link to 鉄道, got %+v", entry)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query+"/"+tc.dictType, func(t *testing.T) {
+			result, err := d.Lookup(tc.query)
+			if err != nil {
+				t.Fatalf("Lookup error: %v", err)
+			}
+			if result == nil {
+				t.Fatalf("Expected a result for %s", tc.query)
+			}
+			if len(result.E[tc.dictType]) != tc.exact || len(result.X[tc.dictType]) != tc.variant {
+				t.Errorf("Expected %d exact and %d variant matches for %s, got %+v", tc.exact, tc.variant, tc.query, result)
+			}
+		})
+	}
+}
+
+func TestCognateLinks(t *testing.T) {
+	d := buildTestDictionary(t, []common.Entry{
+		jmdict.Word{
+			ID:    "1000020",
+			Kanji: []jmdict.KanjiEntry{{Text: "鉄道"}},
+			Kana:  []jmdict.KanaEntry{{Text: "てつどう"}},
+			Sense: []jmdict.Sense{{Gloss: []jmdict.Gloss{{Lang: "eng", Text: "railway"}}}},
+		},
+		chinese_chars.ChineseCharEntry{ID: "3000001", Traditional: "鐵", Simplified: "铁"},
+		chinese_words.ChineseWordEntry{ID: "5000001", Traditional: "鐵道", Simplified: "铁道", Definitions: []string{"railway"}},
+	})
+
+	result, err := d.Lookup("鉄道")
+	if err != nil {
+		t.Fatalf("Lookup error: %v", err)
+	}
+	if result == nil || len(result.E["j"]) != 1 {
+		t.Fatalf("Expected one JMdict match for 鉄道, got %+v", result)
+	}
+	entry, err := d.Entry("j", result.E["j"][0])
+	if err != nil {
+		t.Fatalf("Entry error: %v", err)
+	}
+	word := entry.(jmdict.Word)
+	if len(word.Cognates) != 1 || word.Cognates[0].Text != "鐵道" || !word.Cognates[0].Overlap {
+		t.Fatalf("Expected an overlapping cognate 鐵道, got %+v", word.Cognates)
+	}
+
+	// The link resolves to the Chinese entry, which links back to the word
+	entry, err = d.Entry("w", word.Cognates[0].ID)
+	if err != nil {
+		t.Fatalf("Entry error: %v", err)
+	}
+	chinese := entry.(chinese_words.ChineseWordEntry)
+	if len(chinese.Cognates) != 1 || chinese.Cognates[0].ID != result.E["j"][0] || chinese.Cognates[0].Text != "鉄道" {
+		t.Errorf("Expected a cognate link back to 鉄道, got %+v", chinese.Cognates)
+	}
+}
+
+func TestContainedPositions(t *testing.T) {
+	words := map[string]string{
+		"1000030": "日本",
+		"1000031": "毎日",
+		"1000032": "日曜日",
+		"1000033": "本日中",
+	}
+	var entries []common.Entry
+	for id, kanji := range words {
+		entries = append(entries, jmdict.Word{ID: id, Kanji: []jmdict.KanjiEntry{{Text: kanji}}})
+	}
+	d := buildTestDictionary(t, entries)
+
+	result, err := d.Lookup("日")
+	if err != nil {
+		t.Fatalf("Lookup error: %v", err)
+	}
+	if result == nil || len(result.C["j"]) != len(words) {
+		t.Fatalf("Expected %d contained matches for 日, got %+v", len(words), result)
+	}
+
+	testCases := []struct {
+		name     string
+		pos      processor.Position
+		expected []string
+	}{
+		{"start", processor.PositionStart, []string{"日曜日", "日本"}},
+		{"middle", processor.PositionMiddle, []string{"本日中"}},
+		{"end", processor.PositionEnd, []string{"日曜日", "毎日"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, id := range result.ContainedAt(tc.pos)["j"] {
+				entry, err := d.Entry("j", id)
+				if err != nil {
+					t.Fatalf("Error loading entry %d: %v", id, err)
+				}
+				got = append(got, entry.(jmdict.Word).Kanji[0].Text)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNgramIndex(t *testing.T) {
+	entries := []common.Entry{
+		jmdict.Word{ID: "1000040", Kanji: []jmdict.KanjiEntry{{Text: "日本"}}},
+		jmdict.Word{ID: "1000041", Kanji: []jmdict.KanjiEntry{{Text: "日本語"}}},
+		jmdict.Word{ID: "1000042", Kanji: []jmdict.KanjiEntry{{Text: "日本人", Common: true}}},
+		jmdict.Word{ID: "1000043", Kanji: []jmdict.KanjiEntry{{Text: "北日本"}}},
+		jmdict.Word{ID: "1000044", Kanji: []jmdict.KanjiEntry{{Text: "日本料理店"}}},
+		jmdict.Word{ID: "1000045", Kanji: []jmdict.KanjiEntry{{Text: "日本らしい"}}},
+	}
+
+	ngrams := processor.NgramConfig{MinLength: 2, MaxLength: 4, MaxPostings: 3}
+	d := buildTestDictionaryWith(t, entries, func(p *processor.ShardedIndexProcessor) {
+		p.SetNgrams(ngrams)
+	})
+
+	result, err := d.Lookup("日本")
+	if err != nil {
+		t.Fatalf("Lookup error: %v", err)
+	}
+	if result == nil {
+		t.Fatal("Expected a result for 日本")
+	}
+
+	var got []string
+	for _, id := range result.G["j"] {
+		entry, err := d.Entry("j", id)
+		if err != nil {
+			t.Fatalf("Error loading entry %d: %v", id, err)
+		}
+		got = append(got, entry.(jmdict.Word).Kanji[0].Text)
+	}
+
+	// Common words first, then shorter ones, trimmed to three; 日本 itself is an exact match
+	expected := []string{"日本人", "日本語", "北日本"}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected n-gram matches %v, got %v", expected, got)
+	}
+
+	// Substrings with kana are only indexed when enabled
+	result, err = d.Lookup("本ら")
+	if err != nil {
+		t.Fatalf("Lookup error: %v", err)
+	}
+	if result != nil && len(result.G["j"]) > 0 {
+		t.Errorf("Expected no kana n-gram matches, got %v", result.G)
+	}
+}
+
+func TestConsolidatedKeysLookup(t *testing.T) {
+	d := buildTestDictionaryWith(t, []common.Entry{
+		jmdict.Word{ID: "1000050", Kanji: []jmdict.KanjiEntry{{Text: "日本"}}, Kana: []jmdict.KanaEntry{{Text: "にほん"}}},
+		jmdict.Word{ID: "1000051", Kanji: []jmdict.KanjiEntry{{Text: "日"}}, Kana: []jmdict.KanaEntry{{Text: "ひ"}}},
+	}, func(p *processor.ShardedIndexProcessor) {
+		p.SetConsolidateKeys(true)
+	})
+
+	// にほん lives in the non-Han shard and points at an entry in the two-character shard
+	result, err := d.Lookup("にほん")
+	if err != nil {
+		t.Fatalf("Lookup error: %v", err)
+	}
+	if result == nil || len(result.E["j"]) != 1 {
+		t.Fatalf("Expected one exact match for にほん, got %+v", result)
+	}
+	entry, err := d.Entry("j", result.E["j"][0])
+	if err != nil {
+		t.Fatalf("Error loading entry: %v", err)
+	}
+	if entry.(jmdict.Word).ID != "1000050" {
+		t.Errorf("Expected entry 1000050, got %s", entry.GetID())
+	}
+
+	result, err = d.Lookup("日")
+	if err != nil {
+		t.Fatalf("Lookup error: %v", err)
+	}
+	if result == nil || len(result.E["j"]) != 1 || len(result.C["j"]) != 1 {
+		t.Errorf("Expected one exact and one contained match for 日, got %+v", result)
+	}
+}
+
+func TestSearchComponents(t *testing.T) {
+	entries := []common.Entry{
+		kanjidic.Kanji{Character: "海", Stroke: 9, Frequency: 200},
+		kanjidic.Kanji{Character: "梅", Stroke: 10, Frequency: 1000},
+		kanjidic.Kanji{Character: "池", Stroke: 6, Frequency: 800},
+	}
+	index := components.FromEntries(append([]common.Entry{
+		ids.IDSEntry{Character: "海", IDS: "⿰氵每"},
+		ids.IDSEntry{Character: "梅", IDS: "⿰木每"},
+		ids.IDSEntry{Character: "池", IDS: "⿰氵也"},
+		ids.IDSEntry{Character: "每", IDS: "⿱𠂉母"},
+	}, entries...))
+
+	d := buildTestDictionaryWith(t, entries, func(p *processor.ShardedIndexProcessor) {
+		p.SetComponents(index)
+	})
+
+	testCases := []struct {
+		components []string
+		expected   []string
+	}{
+		{[]string{"氵"}, []string{"池", "海"}},
+		{[]string{"氵", "每"}, []string{"海"}},
+		{[]string{"母"}, []string{"海", "梅", "每"}},
+		{[]string{"氵", "木"}, nil},
+		{[]string{"火"}, nil},
+	}
+
+	for _, tc := range testCases {
+		got, err := d.SearchComponents(tc.components...)
+		if err != nil {
+			t.Fatalf("SearchComponents error: %v", err)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+			t.Errorf("SearchComponents(%v) = %v, want %v", tc.components, got, tc.expected)
+		}
+	}
+}
+
+func TestBrowse(t *testing.T) {
+	skip := func(codes ...string) []kanjidic.QueryRef {
+		var refs []kanjidic.QueryRef
+		for _, code := range codes {
+			refs = append(refs, kanjidic.QueryRef{Type: "skip", Value: code})
+		}
+		return refs
+	}
+	entries := []common.Entry{
+		kanjidic.Kanji{Character: "海", NumericID: "1", Stroke: 9, Frequency: 200, Radical: 85,
+			QueryCodes: append(skip("1-3-6"), kanjidic.QueryRef{Type: "four_corner", Value: "3815.7"})},
+		kanjidic.Kanji{Character: "池", NumericID: "2", Stroke: 6, Frequency: 800, Radical: 85, QueryCodes: skip("1-3-3")},
+		kanjidic.Kanji{Character: "泳", NumericID: "3", Stroke: 8, Frequency: 1200, Radical: 85, QueryCodes: skip("1-3-5")},
+		kanjidic.Kanji{Character: "汎", NumericID: "4", Stroke: 6, Radical: 85, QueryCodes: skip("1-3-3")},
+		kanjidic.Kanji{Character: "沈", NumericID: "5", Stroke: 7, Frequency: 1500, Radical: 85,
+			QueryCodes: []kanjidic.QueryRef{{Type: "skip", Value: "1-3-4"}, {Type: "skip", Value: "1-3-3", Misclass: "stroke_count"}}},
+		kanjidic.Kanji{Character: "日", NumericID: "6", Stroke: 4, Frequency: 1, Radical: 72, QueryCodes: skip("3-3-1")},
+	}
+	d := buildTestDictionary(t, entries)
+
+	characters := func(ids []int64) string {
+		var chars []string
+		for _, id := range ids {
+			entry, err := d.Entry("d", id)
+			if err != nil {
+				t.Fatalf("Error loading entry %d: %v", id, err)
+			}
+			chars = append(chars, entry.(kanjidic.Kanji).Character)
+		}
+		return strings.Join(chars, "")
+	}
+
+	testCases := []struct {
+		name   string
+		browse func() ([]int64, error)
+		want   string
+	}{
+		// Most frequent first, unranked last, misclassifications included
+		{"skip 1-3-3", func() ([]int64, error) { return d.BrowseSKIP("1-3-3") }, "池沈汎"},
+		{"skip 3-3-1", func() ([]int64, error) { return d.BrowseSKIP("3-3-1") }, "日"},
+		{"four corner", func() ([]int64, error) { return d.BrowseFourCorner("3815.7") }, "海"},
+		// 海 is 水 (4 strokes) + 5, or 氵 (3 strokes) + 6
+		{"radical 85-6", func() ([]int64, error) { return d.BrowseRadical(85, 6) }, "海"},
+		{"radical 85-5", func() ([]int64, error) { return d.BrowseRadical(85, 5) }, "海泳"},
+		{"radical 72-0", func() ([]int64, error) { return d.BrowseRadical(72, 0) }, "日"},
+		{"missing", func() ([]int64, error) { return d.BrowseSKIP("4-1-1") }, ""},
+	}
+	for _, tc := range testCases {
+		ids, err := tc.browse()
+		if err != nil {
+			t.Fatalf("%s: browse error: %v", tc.name, err)
+		}
+		if got := characters(ids); got != tc.want {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+
+	if processor.RadicalStrokes(1) != 1 || processor.RadicalStrokes(85) != 4 || processor.RadicalStrokes(214) != 17 || processor.RadicalStrokes(215) != 0 {
+		t.Error("Unexpected radical stroke counts")
+	}
+}
+
+func TestSearchRadicals(t *testing.T) {
+	entries := []common.Entry{
+		kanjidic.Kanji{Character: "亜", NumericID: "1", Stroke: 7, Frequency: 1509},
+		kanjidic.Kanji{Character: "唖", NumericID: "2", Stroke: 10},
+		kanjidic.Kanji{Character: "海", NumericID: "3", Stroke: 9, Frequency: 200},
+	}
+	table := kradfile.FromEntries(append([]common.Entry{
+		kradfile.Decomposition{Kanji: "亜", Components: []string{"一", "｜", "口"}},
+		kradfile.Decomposition{Kanji: "唖", Components: []string{"一", "｜", "口"}},
+		kradfile.Decomposition{Kanji: "海", Components: []string{"毋", "汁", "乙", "一"}},
+		kradfile.Radical{Radical: "一", Strokes: 1, Kanji: []string{"亜", "唖", "海"}},
+		kradfile.Radical{Radical: "｜", Strokes: 1, Kanji: []string{"亜", "唖"}},
+		kradfile.Radical{Radical: "口", Strokes: 3, Kanji: []string{"亜", "唖"}},
+		kradfile.Radical{Radical: "汁", Strokes: 3, Kanji: []string{"海"}},
+	}, entries...))
+
+	d := buildTestDictionaryWith(t, entries, func(p *processor.ShardedIndexProcessor) {
+		p.SetRadicals(table)
+	})
+
+	radicals, err := d.Radicals()
+	if err != nil {
+		t.Fatalf("Radicals error: %v", err)
+	}
+	if len(radicals) != 4 || radicals[3].Radical != "汁" || radicals[3].Strokes != 3 {
+		t.Fatalf("Unexpected radical table %+v", radicals)
+	}
+
+	testCases := []struct {
+		radicals []string
+		expected string
+	}{
+		{[]string{"一"}, "亜海唖"},
+		{[]string{"口", "一"}, "亜唖"},
+		{[]string{"汁", "口"}, ""},
+		{[]string{"火"}, ""},
+	}
+	for _, tc := range testCases {
+		got, err := d.SearchRadicals(tc.radicals...)
+		if err != nil {
+			t.Fatalf("SearchRadicals error: %v", err)
+		}
+		if strings.Join(got, "") != tc.expected {
+			t.Errorf("SearchRadicals(%v) = %v, want %s", tc.radicals, got, tc.expected)
+		}
+	}
+
+	// Kanjidic entries carry their radicals
+	result, err := d.Lookup("海")
+	if err != nil || result == nil || len(result.E["d"]) != 1 {
+		t.Fatalf("Expected a Kanjidic match for 海, got %+v (%v)", result, err)
+	}
+	entry, err := d.Entry("d", result.E["d"][0])
+	if err != nil {
+		t.Fatalf("Error loading entry: %v", err)
+	}
+	if parts := entry.(kanjidic.Kanji).Parts; strings.Join(parts, "") != "毋汁乙一" {
+		t.Errorf("Expected the parts of 海, got %v", parts)
+	}
+}
+
+func TestStrokes(t *testing.T) {
+	entries := []common.Entry{
+		kanjidic.Kanji{Character: "一", NumericID: "1", Stroke: 1},
+		kanjidic.Kanji{Character: "二", NumericID: "2", Stroke: 2},
+		chinese_chars.ChineseCharEntry{ID: "50", Traditional: "一", Simplified: "一", StrokeCount: 1},
+	}
+	strokes := map[string]kanjivg.Character{
+		"一": {ID: "04e00", Character: "一", Strokes: []kanjivg.Stroke{{Type: "㇐", Path: "M11,54.25c3.19,0.62"}},
+			Root: &kanjivg.Group{Element: "一", Strokes: []int{0}}},
+	}
+	d := buildTestDictionaryWith(t, entries, func(p *processor.ShardedIndexProcessor) {
+		p.SetStrokes(strokes)
+	})
+
+	result, err := d.Lookup("一")
+	if err != nil || result == nil || len(result.E["d"]) != 1 || len(result.E["c"]) != 1 {
+		t.Fatalf("Expected Kanjidic and Chinese character matches for 一, got %+v (%v)", result, err)
+	}
+	for _, dictType := range []string{"d", "c"} {
+		got, err := d.Strokes(dictType, result.E[dictType][0])
+		if err != nil {
+			t.Fatalf("Strokes error: %v", err)
+		}
+		if got == nil || !reflect.DeepEqual(got.Strokes, strokes["一"].Strokes) || got.Root.Element != "一" {
+			t.Errorf("Expected the strokes of 一 for %s, got %+v", dictType, got)
+		}
+	}
+
+	// Characters without KanjiVG data have no companion file
+	result, err = d.Lookup("二")
+	if err != nil || result == nil || len(result.E["d"]) != 1 {
+		t.Fatalf("Expected a Kanjidic match for 二, got %+v (%v)", result, err)
+	}
+	if got, err := d.Strokes("d", result.E["d"][0]); err != nil || got != nil {
+		t.Errorf("Expected no strokes for 二, got %+v (%v)", got, err)
+	}
+}
+
+func TestHanzi(t *testing.T) {
+	entries := []common.Entry{
+		chinese_chars.ChineseCharEntry{ID: "50", Traditional: "海", Simplified: "海", StrokeCount: 10},
+		chinese_chars.ChineseCharEntry{ID: "51", Traditional: "水", Simplified: "水", StrokeCount: 4},
+	}
+	hanzi := map[string]makemeahanzi.Character{
+		"海": {Character: "海", Decomposition: "⿰氵每", Radical: "氵",
+			Etymology: &chinese_chars.Etymology{Type: "pictophonetic", Semantic: "氵", Phonetic: "每", Hint: "water"},
+			Strokes:   []string{"M 1 1", "M 2 2"}, Medians: [][][2]int{{{1, 1}}, {{2, 2}}}, Matches: [][]int{{0}, {1}}},
+		"水": {Character: "水", Decomposition: "？", Radical: "水",
+			Etymology: &chinese_chars.Etymology{Type: "pictographic", Hint: "Water flowing"}},
+	}
+	d := buildTestDictionaryWith(t, entries, func(p *processor.ShardedIndexProcessor) {
+		p.SetHanzi(hanzi)
+	})
+
+	result, err := d.Lookup("海")
+	if err != nil || result == nil || len(result.E["c"]) != 1 {
+		t.Fatalf("Expected a Chinese character match for 海, got %+v (%v)", result, err)
+	}
+	entry, err := d.Entry("c", result.E["c"][0])
+	if err != nil {
+		t.Fatalf("Error loading entry: %v", err)
+	}
+	sea := entry.(chinese_chars.ChineseCharEntry)
+	if sea.Decomposition != "⿰氵每" || sea.Radical != "氵" || sea.Etymology == nil || sea.Etymology.Phonetic != "每" {
+		t.Errorf("Expected Make Me a Hanzi data on 海, got %+v", sea)
+	}
+	graphics, err := d.Graphics(result.E["c"][0])
+	if err != nil {
+		t.Fatalf("Graphics error: %v", err)
+	}
+	if graphics == nil || graphics.Character != "海" || len(graphics.Strokes) != 2 || !reflect.DeepEqual(graphics.Matches, hanzi["海"].Matches) {
+		t.Errorf("Expected the graphics of 海, got %+v", graphics)
+	}
+
+	// Characters without graphics have no companion file
+	result, err = d.Lookup("水")
+	if err != nil || result == nil || len(result.E["c"]) != 1 {
+		t.Fatalf("Expected a Chinese character match for 水, got %+v (%v)", result, err)
+	}
+	if got, err := d.Graphics(result.E["c"][0]); err != nil || got != nil {
+		t.Errorf("Expected no graphics for 水, got %+v (%v)", got, err)
+	}
+}
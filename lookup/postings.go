@@ -0,0 +1,87 @@
+package lookup
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"kiokun-go/processor"
+)
+
+// DecodePostings decodes a posting list written by processor.EncodePostings
+func DecodePostings(encoded string) ([]int64, error) {
+	buf, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding posting list: %v", err)
+	}
+
+	var ids []int64
+	var prev int64
+	for len(buf) > 0 {
+		v, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return nil, fmt.Errorf("malformed posting list varint at byte %d", len(buf))
+		}
+		buf = buf[n:]
+		prev += int64(v>>1) ^ -int64(v&1)
+		ids = append(ids, prev)
+	}
+	return ids, nil
+}
+
+// decodeIndexEntry decodes an index file in either format: the plain IndexEntry, or
+// the processor.CompactIndexEntry written with compact postings, marked by its z field
+func decodeIndexEntry(data json.RawMessage) (*processor.IndexEntry, error) {
+	var version struct {
+		Z int `json:"z"`
+	}
+	if err := json.Unmarshal(data, &version); err != nil {
+		return nil, err
+	}
+
+	switch version.Z {
+	case 0:
+		var entry processor.IndexEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, err
+		}
+		return &entry, nil
+	case processor.CompactVersion:
+		var compact processor.CompactIndexEntry
+		if err := json.Unmarshal(data, &compact); err != nil {
+			return nil, err
+		}
+		return expandCompact(&compact)
+	default:
+		return nil, fmt.Errorf("unsupported index encoding version %d", version.Z)
+	}
+}
+
+// expandCompact decodes the posting lists of a compact index entry
+func expandCompact(c *processor.CompactIndexEntry) (*processor.IndexEntry, error) {
+	entry := &processor.IndexEntry{F: c.F, P: c.P, A: c.A, K: c.K}
+	for _, section := range []struct {
+		encoded map[string]string
+		decoded *map[string][]int64
+	}{
+		{c.E, &entry.E},
+		{c.C, &entry.C},
+		{c.G, &entry.G},
+		{c.R, &entry.R},
+		{c.Y, &entry.Y},
+	} {
+		if len(section.encoded) == 0 {
+			continue
+		}
+		*section.decoded = make(map[string][]int64, len(section.encoded))
+		for dictType, encoded := range section.encoded {
+			ids, err := DecodePostings(encoded)
+			if err != nil {
+				return nil, err
+			}
+			(*section.decoded)[dictType] = ids
+		}
+	}
+	return entry, nil
+}
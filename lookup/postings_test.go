@@ -0,0 +1,160 @@
+package lookup
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/jmdict"
+	"kiokun-go/processor"
+)
+
+func TestDecodePostings(t *testing.T) {
+	testCases := [][]int64{
+		nil,
+		{31000001},
+		{11000010, 21000020, 31000030, 31000031},
+		{31000031, 11000010, 21000020}, // Ranked n-gram lists are not sorted
+		{-8825745617431146327, 0, 8825745617431146327},
+	}
+
+	for _, ids := range testCases {
+		got, err := DecodePostings(processor.EncodePostings(ids))
+		if err != nil {
+			t.Fatalf("DecodePostings error for %v: %v", ids, err)
+		}
+		if !reflect.DeepEqual(got, ids) {
+			t.Errorf("Round trip of %v gave %v", ids, got)
+		}
+	}
+
+	if _, err := DecodePostings("gA=="); err == nil {
+		t.Error("Expected an error for a truncated varint")
+	}
+}
+
+func TestCompactIndexLookup(t *testing.T) {
+	entries := []common.Entry{
+		jmdict.Word{ID: "1000060", Kanji: []jmdict.KanjiEntry{{Text: "日本"}}, Kana: []jmdict.KanaEntry{{Text: "にほん"}}},
+		jmdict.Word{ID: "1000061", Kanji: []jmdict.KanjiEntry{{Text: "毎日"}}, Kana: []jmdict.KanaEntry{{Text: "まいにち"}}},
+		jmdict.Word{ID: "1000062", Kanji: []jmdict.KanjiEntry{{Text: "日"}, {Text: "陽"}}, Kana: []jmdict.KanaEntry{{Text: "ひ"}}},
+		jmdict.Word{ID: "1000063", Kanji: []jmdict.KanjiEntry{{Text: "本日中"}}},
+	}
+
+	plain := buildTestDictionary(t, entries)
+	compact := buildTestDictionaryWith(t, entries, func(p *processor.ShardedIndexProcessor) {
+		p.SetCompactPostings(true)
+	})
+
+	for _, query := range []string{"日", "陽", "にほん", "nihon"} {
+		t.Run(query, func(t *testing.T) {
+			want, err := plain.Lookup(query)
+			if err != nil {
+				t.Fatalf("Lookup error: %v", err)
+			}
+			got, err := compact.Lookup(query)
+			if err != nil {
+				t.Fatalf("Lookup error: %v", err)
+			}
+			if want == nil || got == nil {
+				t.Fatalf("Expected results for %s, got %+v and %+v", query, want, got)
+			}
+
+			// Compact lists are sorted, so compare the IDs with their parallel values
+			for dictType, ids := range want.E {
+				if len(got.E[dictType]) != len(ids) {
+					t.Fatalf("Expected exact matches %v, got %v", want.E, got.E)
+				}
+				for _, id := range ids {
+					wantForm, wantOK := want.MatchedForm(dictType, id)
+					gotForm, gotOK := got.MatchedForm(dictType, id)
+					if wantForm != gotForm || wantOK != gotOK {
+						t.Errorf("Exact match %d: expected form %d, got %d", id, wantForm, gotForm)
+					}
+				}
+			}
+			for dictType, ids := range want.C {
+				if len(got.C[dictType]) != len(ids) {
+					t.Fatalf("Expected contained matches %v, got %v", want.C, got.C)
+				}
+				for _, id := range ids {
+					wantPos, _ := want.ContainedPosition(dictType, id)
+					gotPos, _ := got.ContainedPosition(dictType, id)
+					if wantPos != gotPos {
+						t.Errorf("Contained match %d: expected position %d, got %d", id, wantPos, gotPos)
+					}
+				}
+			}
+			if len(got.R["j"]) != len(want.R["j"]) {
+				t.Errorf("Expected romaji matches %v, got %v", want.R, got.R)
+			}
+		})
+	}
+}
+
+// benchmarkPostings is a large sorted posting list, like the contained matches of 日
+func benchmarkPostings() []int64 {
+	ids := make([]int64, 50000)
+	for i := range ids {
+		ids[i] = 31000000 + int64(i)*37
+	}
+	return ids
+}
+
+func BenchmarkDecodeJSONPostings(b *testing.B) {
+	data, err := json.Marshal(map[string][]int64{"j": benchmarkPostings()})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var lists map[string][]int64
+		if err := json.Unmarshal(data, &lists); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(len(data)), "bytes")
+}
+
+func BenchmarkDecodeCompactPostings(b *testing.B) {
+	data, err := json.Marshal(map[string]string{"j": processor.EncodePostings(benchmarkPostings())})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var lists map[string]string
+		if err := json.Unmarshal(data, &lists); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := DecodePostings(lists["j"]); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(len(data)), "bytes")
+}
+
+func TestMergeIndexEntry(t *testing.T) {
+	dst := &processor.IndexEntry{
+		E: map[string][]int64{"j": {1, 2}},
+		F: map[string][]int{"j": {0, 1}},
+		C: map[string][]int64{"j": {5}},
+		P: map[string][]processor.Position{"j": {processor.PositionStart}},
+	}
+	mergeIndexEntry(dst, &processor.IndexEntry{
+		E: map[string][]int64{"j": {2, 3, 3}},
+		F: map[string][]int{"j": {1, 2, 2}},
+		C: map[string][]int64{"j": {5, 6}},
+		P: map[string][]processor.Position{"j": {processor.PositionStart, processor.PositionEnd}},
+	})
+
+	if !reflect.DeepEqual(dst.E["j"], []int64{1, 2, 3}) || !reflect.DeepEqual(dst.F["j"], []int{0, 1, 2}) {
+		t.Errorf("Unexpected exact matches: %v %v", dst.E, dst.F)
+	}
+	if !reflect.DeepEqual(dst.C["j"], []int64{5, 6}) || !reflect.DeepEqual(dst.P["j"], []processor.Position{processor.PositionStart, processor.PositionEnd}) {
+		t.Errorf("Unexpected contained matches: %v %v", dst.C, dst.P)
+	}
+}
@@ -0,0 +1,202 @@
+package lookup
+
+import (
+	"strings"
+	"unicode"
+
+	"kiokun-go/deinflect"
+	"kiokun-go/dictionaries/jmdict"
+	"kiokun-go/processor"
+)
+
+// maxTokenLength is the longest span, in characters, tried as a single word
+const maxTokenLength = 16
+
+// Token is one segment of scanned text
+type Token struct {
+	Text    string                // Text as it appears in the input
+	Start   int                   // Byte offset of the token in the input
+	End     int                   // Byte offset just past the token
+	Base    string                // Index key that matched (the dictionary form for inflected words)
+	Reasons []string              // Inflections removed to reach Base, outermost first
+	Match   *processor.IndexEntry // Exact matches for Base; nil for text with no dictionary entry
+}
+
+// Scan splits running Japanese or Chinese text into words by longest match against
+// the exact-match keys of every shard. At each position the longest span that is an
+// index key wins; Japanese spans are also deinflected (食べなかった → 食べる) and kept
+// only if a JMdict entry of the right part of speech exists. Characters that start no
+// match become single-character tokens, and runs of other text (spaces, punctuation,
+// Latin letters) become one token each, both with a nil Match.
+func (d *Dictionary) Scan(text string) ([]Token, error) {
+	s := &scanner{d: d, index: make(map[string]*processor.IndexEntry), words: make(map[int64]*jmdict.Word)}
+
+	var tokens []Token
+	runes := []rune(text)
+	offsets := make([]int, len(runes)+1)
+	for i, r := range runes {
+		offsets[i+1] = offsets[i] + len(string(r))
+	}
+
+	for i := 0; i < len(runes); {
+		if !isScannable(runes[i]) {
+			end := i + 1
+			for end < len(runes) && !isScannable(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, Token{Text: string(runes[i:end]), Start: offsets[i], End: offsets[end]})
+			i = end
+			continue
+		}
+
+		token := Token{Text: string(runes[i : i+1]), Start: offsets[i], End: offsets[i+1]}
+		for end := min(len(runes), i+maxTokenLength); end > i; end-- {
+			if !isScannable(runes[end-1]) {
+				continue
+			}
+			span := string(runes[i:end])
+			match, base, reasons, err := s.match(span)
+			if err != nil {
+				return nil, err
+			}
+			if match != nil {
+				token = Token{Text: span, Start: offsets[i], End: offsets[end], Base: base, Reasons: reasons, Match: match}
+				break
+			}
+		}
+
+		tokens = append(tokens, token)
+		i += len([]rune(token.Text))
+	}
+
+	return tokens, nil
+}
+
+// scanner caches index and entry reads for one Scan call
+type scanner struct {
+	d     *Dictionary
+	index map[string]*processor.IndexEntry
+	words map[int64]*jmdict.Word
+}
+
+// lookup reads the merged index entry for a key, remembering misses
+func (s *scanner) lookup(key string) (*processor.IndexEntry, error) {
+	if entry, ok := s.index[key]; ok {
+		return entry, nil
+	}
+	entry, err := s.d.Index(key)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && len(entry.E) == 0 {
+		entry = nil
+	}
+	s.index[key] = entry
+	return entry, nil
+}
+
+// word loads a JMdict entry, remembering it for later spans
+func (s *scanner) word(id int64) (*jmdict.Word, error) {
+	if w, ok := s.words[id]; ok {
+		return w, nil
+	}
+	entry, err := s.d.Entry("j", id)
+	if err != nil {
+		return nil, err
+	}
+	w := entry.(jmdict.Word)
+	s.words[id] = &w
+	return &w, nil
+}
+
+// match finds the exact matches for a span, trying it as written first and then
+// each deinflected form. It returns a nil entry when nothing matches.
+func (s *scanner) match(span string) (*processor.IndexEntry, string, []string, error) {
+	entry, err := s.lookup(span)
+	if err != nil || entry != nil {
+		return entry, span, nil, err
+	}
+	if !hasKana(span) {
+		return nil, "", nil, nil
+	}
+
+	for _, c := range deinflect.Deinflect(span)[1:] {
+		keys := []string{c.Text}
+		// Suru nouns are indexed without する (勉強しました → 勉強)
+		if c.Type&deinflect.Suru != 0 && strings.HasSuffix(c.Text, "する") && c.Text != "する" {
+			keys = append(keys, strings.TrimSuffix(c.Text, "する"))
+		}
+
+		for _, key := range keys {
+			entry, err := s.lookup(key)
+			if err != nil {
+				return nil, "", nil, err
+			}
+			if entry == nil {
+				continue
+			}
+			filtered, err := s.filterWords(entry, c.Type)
+			if err != nil {
+				return nil, "", nil, err
+			}
+			if filtered != nil {
+				return filtered, key, c.Reasons, nil
+			}
+		}
+	}
+	return nil, "", nil, nil
+}
+
+// filterWords keeps the JMdict exact matches with a part of speech of the given type,
+// carrying their matched forms along. It returns nil if none qualify.
+func (s *scanner) filterWords(entry *processor.IndexEntry, typ deinflect.WordType) (*processor.IndexEntry, error) {
+	var result *processor.IndexEntry
+	for _, id := range entry.E["j"] {
+		w, err := s.word(id)
+		if err != nil {
+			return nil, err
+		}
+		if !hasPartOfSpeech(w, typ) {
+			continue
+		}
+
+		if result == nil {
+			result = &processor.IndexEntry{E: map[string][]int64{}}
+		}
+		result.E["j"] = append(result.E["j"], id)
+		if form, ok := entry.MatchedForm("j", id); ok {
+			if result.F == nil {
+				result.F = map[string][]int{}
+			}
+			result.F["j"] = append(result.F["j"], form)
+		}
+	}
+	return result, nil
+}
+
+// hasPartOfSpeech reports whether any sense of a word belongs to the type
+func hasPartOfSpeech(w *jmdict.Word, typ deinflect.WordType) bool {
+	for _, sense := range w.Sense {
+		for _, pos := range sense.PartOfSpeech {
+			if typ.Matches(pos) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isScannable reports whether a character can be part of a dictionary word
+func isScannable(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー' || r == '々' || r == 'ヶ'
+}
+
+// hasKana reports whether text contains kana, the only text that inflects
+func hasKana(text string) bool {
+	for _, r := range text {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana) {
+			return true
+		}
+	}
+	return false
+}
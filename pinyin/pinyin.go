@@ -0,0 +1,315 @@
+package pinyin
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Syllable is a single pinyin syllable. Text is lowercase ASCII with ü written as v.
+// Tone is 1-4, 5 for the neutral tone, or 0 when the input gave no tone.
+type Syllable struct {
+	Text string
+	Tone int
+}
+
+// syllables lists every valid toneless pinyin syllable (ü written as v)
+var syllables = buildSyllables(`
+a ai an ang ao
+ba bai ban bang bao bei ben beng bi bian biao bie bin bing bo bu
+ca cai can cang cao ce cei cen ceng cha chai chan chang chao che chen cheng chi chong chou chu chua chuai chuan chuang chui chun chuo ci cong cou cu cuan cui cun cuo
+da dai dan dang dao de dei den deng di dia dian diao die ding diu dong dou du duan dui dun duo
+e ei en eng er
+fa fan fang fei fen feng fiao fo fou fu
+ga gai gan gang gao ge gei gen geng gong gou gu gua guai guan guang gui gun guo
+ha hai han hang hao he hei hen heng hong hou hu hua huai huan huang hui hun huo
+ji jia jian jiang jiao jie jin jing jiong jiu ju juan jue jun
+ka kai kan kang kao ke kei ken keng kong kou ku kua kuai kuan kuang kui kun kuo
+la lai lan lang lao le lei leng li lia lian liang liao lie lin ling liu lo long lou lu luan lun luo lv lve
+ma mai man mang mao me mei men meng mi mian miao mie min ming miu mo mou mu
+na nai nan nang nao ne nei nen neng ni nian niang niao nie nin ning niu nong nou nu nuan nuo nv nve
+o ou
+pa pai pan pang pao pei pen peng pi pian piao pie pin ping po pou pu
+qi qia qian qiang qiao qie qin qing qiong qiu qu quan que qun
+ran rang rao re ren reng ri rong rou ru rua ruan rui run ruo
+sa sai san sang sao se sen seng sha shai shan shang shao she shei shen sheng shi shou shu shua shuai shuan shuang shui shun shuo si song sou su suan sui sun suo
+ta tai tan tang tao te tei teng ti tian tiao tie ting tong tou tu tuan tui tun tuo
+wa wai wan wang wei wen weng wo wu
+xi xia xian xiang xiao xie xin xing xiong xiu xu xuan xue xun
+ya yan yang yao ye yi yin ying yo yong you yu yuan yue yun
+za zai zan zang zao ze zei zen zeng zha zhai zhan zhang zhao zhe zhei zhen zheng zhi zhong zhou zhu zhua zhuai zhuan zhuang zhui zhun zhuo zi zong zou zu zuan zui zun zuo
+r m n ng hm hng
+`)
+
+// maxSyllableLen is the length of the longest syllable (zhuang, chuang, shuang)
+const maxSyllableLen = 6
+
+// buildSyllables turns a whitespace-separated list into a set
+func buildSyllables(list string) map[string]bool {
+	m := make(map[string]bool)
+	for _, s := range strings.Fields(list) {
+		m[s] = true
+	}
+	return m
+}
+
+// toneMarks maps each vowel (v for ü) to its marked forms for tones 1-4
+var toneMarks = map[rune][4]rune{
+	'a': {'ā', 'á', 'ǎ', 'à'},
+	'e': {'ē', 'é', 'ě', 'è'},
+	'i': {'ī', 'í', 'ǐ', 'ì'},
+	'o': {'ō', 'ó', 'ǒ', 'ò'},
+	'u': {'ū', 'ú', 'ǔ', 'ù'},
+	'v': {'ǖ', 'ǘ', 'ǚ', 'ǜ'},
+}
+
+// unmarked maps a tone-marked vowel back to its base vowel and tone
+var unmarked = buildUnmarked()
+
+// buildUnmarked inverts toneMarks
+func buildUnmarked() map[rune]Syllable {
+	m := make(map[rune]Syllable)
+	for base, marks := range toneMarks {
+		for i, r := range marks {
+			m[r] = Syllable{Text: string(base), Tone: i + 1}
+		}
+	}
+	return m
+}
+
+// Parse splits pinyin in any common form ("rì běn", "Rìběn", "ri4ben3", "ri4 ben3",
+// "riben", "lu:4", "lü") into syllables. Spaces, apostrophes and hyphens are treated
+// as syllable boundaries; unseparated runs are segmented against the syllable table.
+// It reports false if the input is not valid pinyin.
+func Parse(s string) ([]Syllable, bool) {
+	s = strings.ReplaceAll(strings.ToLower(s), "u:", "v")
+
+	var result []Syllable
+	var letters []rune
+	var tones []int // tone mark found at each letter position, 0 if none
+
+	// flush segments the pending letters, applying a trailing tone number to the last syllable
+	flush := func(toneNumber int) bool {
+		if len(letters) == 0 {
+			return toneNumber == 0
+		}
+		lengths, ok := segment(string(letters))
+		if !ok {
+			return false
+		}
+		pos := 0
+		for i, n := range lengths {
+			syl := Syllable{Text: string(letters[pos : pos+n])}
+			for _, t := range tones[pos : pos+n] {
+				if t != 0 {
+					syl.Tone = t
+				}
+			}
+			if i == len(lengths)-1 && toneNumber != 0 {
+				if syl.Tone != 0 && syl.Tone != toneNumber {
+					return false
+				}
+				syl.Tone = toneNumber
+			}
+			result = append(result, syl)
+			pos += n
+		}
+		letters, tones = letters[:0], tones[:0]
+		return true
+	}
+
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z':
+			letters = append(letters, r)
+			tones = append(tones, 0)
+		case r == 'ü':
+			letters = append(letters, 'v')
+			tones = append(tones, 0)
+		case r >= '1' && r <= '5':
+			if !flush(int(r - '0')) {
+				return nil, false
+			}
+		case r == '\'' || r == '’' || r == '-' || r == '·' || r == ',' || unicode.IsSpace(r):
+			if !flush(0) {
+				return nil, false
+			}
+		default:
+			marked, ok := unmarked[r]
+			if !ok {
+				return nil, false
+			}
+			letters = append(letters, rune(marked.Text[0]))
+			tones = append(tones, marked.Tone)
+		}
+	}
+	if !flush(0) {
+		return nil, false
+	}
+
+	return result, len(result) > 0
+}
+
+// segment splits a run of toneless letters into syllable lengths, preferring the
+// fewest syllables so "xian" stays one syllable rather than "xi an"
+func segment(s string) ([]int, bool) {
+	n := len(s)
+	best := make([][]int, n+1)
+	reachable := make([]bool, n+1)
+	reachable[0] = true
+
+	for i := 0; i < n; i++ {
+		if !reachable[i] {
+			continue
+		}
+		for l := 1; l <= maxSyllableLen && i+l <= n; l++ {
+			if !syllables[s[i:i+l]] {
+				continue
+			}
+			next := append(append([]int{}, best[i]...), l)
+			if !reachable[i+l] || len(next) < len(best[i+l]) {
+				best[i+l] = next
+				reachable[i+l] = true
+			}
+		}
+	}
+
+	return best[n], reachable[n]
+}
+
+// Toneless joins syllables without tones ("riben", ü as v)
+func Toneless(syls []Syllable) string {
+	var b strings.Builder
+	for _, s := range syls {
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
+
+// Numbered joins syllables with tone numbers ("ri4ben3"); the neutral tone is written 5
+func Numbered(syls []Syllable) string {
+	var b strings.Builder
+	for _, s := range syls {
+		b.WriteString(s.Text)
+		tone := s.Tone
+		if tone == 0 {
+			tone = 5
+		}
+		b.WriteString(strconv.Itoa(tone))
+	}
+	return b.String()
+}
+
+// Diacritic joins syllables with tone marks ("rìběn")
+func Diacritic(syls []Syllable) string {
+	var b strings.Builder
+	for _, s := range syls {
+		b.WriteString(markSyllable(s))
+	}
+	return b.String()
+}
+
+// markSyllable places the tone mark: on a or e if present, on the o of ou,
+// otherwise on the last vowel
+func markSyllable(s Syllable) string {
+	runes := []rune(s.Text)
+	pos := -1
+	switch {
+	case strings.ContainsRune(s.Text, 'a'):
+		pos = strings.IndexRune(s.Text, 'a')
+	case strings.ContainsRune(s.Text, 'e'):
+		pos = strings.IndexRune(s.Text, 'e')
+	case strings.Contains(s.Text, "ou"):
+		pos = strings.Index(s.Text, "ou")
+	default:
+		for i, r := range runes {
+			if _, ok := toneMarks[r]; ok {
+				pos = i
+			}
+		}
+	}
+
+	for i, r := range runes {
+		if i == pos && s.Tone >= 1 && s.Tone <= 4 {
+			runes[i] = toneMarks[r][s.Tone-1]
+		} else if r == 'v' {
+			runes[i] = 'ü'
+		}
+	}
+	return string(runes)
+}
+
+// ToNumbered converts pinyin in any form to tone numbers ("rì běn" → "ri4ben3").
+// Invalid input is returned unchanged.
+func ToNumbered(s string) string {
+	syls, ok := Parse(s)
+	if !ok {
+		return s
+	}
+	return Numbered(syls)
+}
+
+// ToDiacritic converts pinyin in any form to tone marks ("ri4 ben3" → "rìběn").
+// Invalid input is returned unchanged.
+func ToDiacritic(s string) string {
+	syls, ok := Parse(s)
+	if !ok {
+		return s
+	}
+	return Diacritic(syls)
+}
+
+// IndexKeys returns the keys under which a pinyin reading is indexed:
+// toneless (riben), numbered (ri4ben3) and diacritic (rìběn).
+// Readings that are not valid pinyin produce no keys.
+func IndexKeys(reading string) []string {
+	syls, ok := Parse(reading)
+	if !ok {
+		return nil
+	}
+
+	var keys []string
+	for _, key := range []string{Toneless(syls), Numbered(syls), Diacritic(syls)} {
+		exists := false
+		for _, k := range keys {
+			if k == key {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// SearchKey normalizes a pinyin query to the index key of the same form:
+// toneless queries stay toneless, numbered queries stay numbered and
+// tone-marked queries stay tone-marked ("Rì běn" → "rìběn", "lu:4" → "lv4").
+// It reports false if the query is not valid pinyin.
+func SearchKey(query string) (string, bool) {
+	syls, ok := Parse(query)
+	if !ok {
+		return "", false
+	}
+
+	switch {
+	case strings.ContainsAny(query, "12345"):
+		return Numbered(syls), true
+	case hasTone(syls):
+		return Diacritic(syls), true
+	default:
+		return Toneless(syls), true
+	}
+}
+
+// hasTone reports whether any syllable carries a tone
+func hasTone(syls []Syllable) bool {
+	for _, s := range syls {
+		if s.Tone != 0 {
+			return true
+		}
+	}
+	return false
+}
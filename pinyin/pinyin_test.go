@@ -0,0 +1,96 @@
+package pinyin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []Syllable
+	}{
+		{"rì běn", []Syllable{{"ri", 4}, {"ben", 3}}},
+		{"Rìběn", []Syllable{{"ri", 4}, {"ben", 3}}},
+		{"ri4ben3", []Syllable{{"ri", 4}, {"ben", 3}}},
+		{"ri4 ben3", []Syllable{{"ri", 4}, {"ben", 3}}},
+		{"riben", []Syllable{{"ri", 0}, {"ben", 0}}},
+		{"lu:4", []Syllable{{"lv", 4}}},
+		{"lǜ", []Syllable{{"lv", 4}}},
+		{"nv3", []Syllable{{"nv", 3}}},
+		{"xian", []Syllable{{"xian", 0}}},
+		{"xi'an", []Syllable{{"xi", 0}, {"an", 0}}},
+		{"zhōngguó", []Syllable{{"zhong", 1}, {"guo", 2}}},
+		{"ma5", []Syllable{{"ma", 5}}},
+	}
+
+	for _, tc := range testCases {
+		got, ok := Parse(tc.input)
+		if !ok {
+			t.Errorf("Expected %s to parse", tc.input)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Parse(%s) = %v, want %v", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestParseRejectsInvalid(t *testing.T) {
+	for _, s := range []string{"", "taberu", "xyz", "ri9", "日本", "bě3"} {
+		if _, ok := Parse(s); ok {
+			t.Errorf("Expected %s not to parse", s)
+		}
+	}
+}
+
+func TestConversions(t *testing.T) {
+	testCases := []struct {
+		input     string
+		numbered  string
+		diacritic string
+	}{
+		{"rì běn", "ri4ben3", "rìběn"},
+		{"ri4 ben3", "ri4ben3", "rìběn"},
+		{"lu:4", "lv4", "lǜ"},
+		{"nv3 er2", "nv3er2", "nǚér"},
+		{"liu2", "liu2", "liú"},
+		{"gui4", "gui4", "guì"},
+		{"dou1", "dou1", "dōu"},
+		{"ma5", "ma5", "ma"},
+	}
+
+	for _, tc := range testCases {
+		if got := ToNumbered(tc.input); got != tc.numbered {
+			t.Errorf("ToNumbered(%s) = %s, want %s", tc.input, got, tc.numbered)
+		}
+		if got := ToDiacritic(tc.input); got != tc.diacritic {
+			t.Errorf("ToDiacritic(%s) = %s, want %s", tc.input, got, tc.diacritic)
+		}
+	}
+}
+
+func TestIndexKeysAndSearchKey(t *testing.T) {
+	keys := IndexKeys("rì běn")
+	expected := []string{"riben", "ri4ben3", "rìběn"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("IndexKeys = %v, want %v", keys, expected)
+	}
+
+	for _, query := range []string{"riben", "ri4ben3", "rìběn", "Ri4 Ben3", "rì běn"} {
+		key, ok := SearchKey(query)
+		if !ok {
+			t.Errorf("Expected %s to be a pinyin query", query)
+			continue
+		}
+		found := false
+		for _, k := range keys {
+			if k == key {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("SearchKey(%s) = %s, not among %v", query, key, keys)
+		}
+	}
+}
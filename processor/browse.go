@@ -0,0 +1,187 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"kiokun-go/dictionaries/kanjidic"
+)
+
+// Browse indexes list Kanjidic characters by the codes of paper dictionary lookup
+// methods, one small file per code: browse/skip/1-4-4.json.br holds the sharded IDs of
+// every kanji with SKIP code 1-4-4, most frequent first
+const (
+	BrowseSKIP       = "skip"        // SKIP codes such as 1-4-4, misclassifications included
+	BrowseFourCorner = "four_corner" // Four-corner codes such as 6010.0
+	BrowseRadical    = "radical"     // Classical radical and remaining strokes, such as 85-6
+)
+
+// BrowseIndexes lists the browse indexes in the order they are written
+var BrowseIndexes = []string{BrowseSKIP, BrowseFourCorner, BrowseRadical}
+
+// radicalStrokes gives the stroke count of each Kangxi radical: radicals up to the
+// first number have one stroke, up to the second two strokes, and so on
+var radicalStrokes = []int{6, 29, 60, 94, 117, 146, 166, 175, 186, 194, 200, 204, 208, 210, 211, 213, 214}
+
+// reducedRadicalStrokes gives the stroke count of the reduced form of radicals that
+// are usually written smaller at the side or top of a character: 氵 for 水, 扌 for 手
+var reducedRadicalStrokes = map[int]int{
+	61:  3, // 忄
+	64:  3, // 扌
+	85:  3, // 氵
+	94:  3, // 犭
+	96:  4, // 王
+	113: 4, // 礻
+	122: 5, // 罒
+	125: 4, // 耂
+	130: 4, // ⺼
+	140: 3, // 艹
+	145: 5, // 衤
+	162: 3, // 辶
+	163: 3, // 阝 on the right
+	170: 3, // 阝 on the left
+}
+
+// RadicalStrokes returns the stroke count of a Kangxi radical, or 0 if the number is
+// not between 1 and 214
+func RadicalStrokes(radical int) int {
+	for i, last := range radicalStrokes {
+		if radical >= 1 && radical <= last {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// RadicalKey returns the radical browse key of a radical and remaining stroke count
+func RadicalKey(radical, remaining int) string {
+	return fmt.Sprintf("%d-%d", radical, remaining)
+}
+
+// browseKey identifies one browse file
+type browseKey struct {
+	shard ShardType
+	index string
+	key   string
+}
+
+// browseEntry is a character listed in a browse file, with what it is ranked by
+type browseEntry struct {
+	id        int64
+	frequency int
+	strokes   int
+}
+
+// browseKeys returns the browse index keys of a kanji. Characters whose radical has a
+// reduced form are listed under the remaining strokes after either form, since it
+// depends on the character which one it is written with.
+func browseKeys(k kanjidic.Kanji) map[string][]string {
+	keys := make(map[string][]string)
+	for _, q := range k.QueryCodes {
+		switch q.Type {
+		case "skip":
+			keys[BrowseSKIP] = appendUnique(keys[BrowseSKIP], q.Value)
+		case "four_corner":
+			keys[BrowseFourCorner] = appendUnique(keys[BrowseFourCorner], q.Value)
+		}
+	}
+
+	if strokes := RadicalStrokes(k.Radical); strokes > 0 && k.Stroke > 0 {
+		for _, s := range []int{strokes, reducedRadicalStrokes[k.Radical]} {
+			if s > 0 && k.Stroke >= s {
+				keys[BrowseRadical] = appendUnique(keys[BrowseRadical], RadicalKey(k.Radical, k.Stroke-s))
+			}
+		}
+	}
+	return keys
+}
+
+// appendUnique appends a key unless the list already has it
+func appendUnique(keys []string, key string) []string {
+	if containsKey(keys, key) {
+		return keys
+	}
+	return append(keys, key)
+}
+
+// addBrowseKeys lists a kanji in the browse files of its codes, in its own shard.
+// The caller must hold p.mu.
+func (p *ShardedIndexProcessor) addBrowseKeys(shardType ShardType, k kanjidic.Kanji, id int64) {
+	if p.browse == nil {
+		p.browse = make(map[browseKey][]browseEntry)
+	}
+	entry := browseEntry{id: id, frequency: k.Frequency, strokes: k.Stroke}
+	for index, keys := range browseKeys(k) {
+		for _, key := range keys {
+			bk := browseKey{shard: shardType, index: index, key: key}
+			p.browse[bk] = append(p.browse[bk], entry)
+		}
+	}
+}
+
+// writeBrowseFiles writes each browse file as a JSON array of sharded IDs, ranked by
+// frequency with unranked characters last, then by stroke count
+func (p *ShardedIndexProcessor) writeBrowseFiles() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for bk, entries := range p.browse {
+		sort.SliceStable(entries, func(i, j int) bool {
+			a, b := entries[i], entries[j]
+			if a.frequency != b.frequency {
+				if a.frequency == 0 || b.frequency == 0 {
+					return b.frequency == 0
+				}
+				return a.frequency < b.frequency
+			}
+			if a.strokes != b.strokes {
+				return a.strokes < b.strokes
+			}
+			return a.id < b.id
+		})
+		ids := make([]int64, len(entries))
+		for i, e := range entries {
+			ids[i] = e.id
+		}
+
+		dir := filepath.Join(p.shardDirs[bk.shard], "browse", bk.index)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := writeCompressedJSON(filepath.Join(dir, BrowseFilename(bk.key)), ids); err != nil {
+			return fmt.Errorf("error writing browse file %s/%s: %v", bk.index, bk.key, err)
+		}
+	}
+	fmt.Printf("Wrote %d browse files\n", len(p.browse))
+	return nil
+}
+
+// RadicalsFilename is the browse file holding the KRADFILE radical picker table
+const RadicalsFilename = "radicals.json.br"
+
+// writeRadicals writes the radicals of the KRADFILE table, each with its stroke count
+// and the kanji containing it, to the browse directory of the single character shard
+func (p *ShardedIndexProcessor) writeRadicals() error {
+	if p.radicals == nil || len(p.radicals.Radicals()) == 0 {
+		return nil
+	}
+
+	dir := filepath.Join(p.shardDirs[ShardHan1Char], "browse")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := writeCompressedJSON(filepath.Join(dir, RadicalsFilename), p.radicals.Radicals()); err != nil {
+		return fmt.Errorf("error writing radical table: %v", err)
+	}
+	fmt.Printf("Wrote radical table with %d radicals\n", len(p.radicals.Radicals()))
+	return nil
+}
+
+// BrowseFilename returns the file name of a browse key. Keys are codes made of
+// digits, dots and dashes, so only path separators need replacing.
+func BrowseFilename(key string) string {
+	return strings.ReplaceAll(key, "/", "_") + ".json.br"
+}
@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/makemeahanzi"
+)
+
+// GraphicsSuffix ends the name of the Make Me a Hanzi stroke file written next to a
+// Chinese character entry: c/<id>.g.json.br beside c/<id>.json.br
+const GraphicsSuffix = ".g.json.br"
+
+// SetHanzi sets the Make Me a Hanzi data added to Chinese character entries, with
+// stroke graphics written as companion files, keyed by character
+func (p *ShardedIndexProcessor) SetHanzi(characters map[string]makemeahanzi.Character) {
+	p.hanzi = characters
+}
+
+// addHanzi adds the decomposition, radical and etymology of a Chinese character and
+// records whether the decomposition agrees with the character's IDS
+func (p *ShardedIndexProcessor) addHanzi(e *chinese_chars.ChineseCharEntry) {
+	hanzi, ok := p.hanzi[e.Traditional]
+	if !ok {
+		return
+	}
+	e.Decomposition = hanzi.Decomposition
+	e.Radical = hanzi.Radical
+	e.Etymology = hanzi.Etymology
+
+	if e.IDS != "" && e.Decomposition != "" && !makemeahanzi.Agrees(e.Decomposition, e.IDS) {
+		p.mu.Lock()
+		if p.hanziMismatches == nil {
+			p.hanziMismatches = make(map[string]string)
+		}
+		p.hanziMismatches[e.Traditional] = fmt.Sprintf("%s %s/%s", e.Traditional, e.Decomposition, e.IDS)
+		p.mu.Unlock()
+	}
+}
+
+// writeGraphics writes the stroke graphics of a Chinese character entry to path, if
+// Make Me a Hanzi has them
+func (p *ShardedIndexProcessor) writeGraphics(entry common.Entry, path string) error {
+	e, ok := entry.(chinese_chars.ChineseCharEntry)
+	if !ok || len(p.hanzi) == 0 {
+		return nil
+	}
+
+	graphics := p.hanzi[e.Traditional].Graphics()
+	if graphics == nil {
+		return nil
+	}
+	return writeCompressedJSON(path, graphics)
+}
+
+// reportHanziMismatches prints the characters whose Make Me a Hanzi
+// decomposition differs from their IDS, as decomposition/IDS
+func (p *ShardedIndexProcessor) reportHanziMismatches() {
+	if len(p.hanziMismatches) == 0 {
+		return
+	}
+
+	mismatches := make([]string, 0, len(p.hanziMismatches))
+	for _, m := range p.hanziMismatches {
+		mismatches = append(mismatches, m)
+	}
+	sort.Strings(mismatches)
+	if len(mismatches) > 20 {
+		mismatches = append(mismatches[:20], "...")
+	}
+	fmt.Printf("Make Me a Hanzi decompositions differ from IDS for %d characters: %s\n", len(p.hanziMismatches), strings.Join(mismatches, ", "))
+}
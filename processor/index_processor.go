@@ -28,6 +28,83 @@ type IndexEntry struct {
 
 	// Contained-in matches (when the key is contained within the entry)
 	C map[string][]int64 `json:"c,omitempty"` // Contained-in matches by dictionary type (j, n, d, c, w)
+
+	// N-gram contained-in matches (when the multi-character key is a substring of the entry), opt-in
+	G map[string][]int64 `json:"g,omitempty"` // Entries containing the key by dictionary type (j, n, w), best ranked first
+
+	// Romaji matches (when the key is a romanized reading of the entry)
+	R map[string][]int64 `json:"r,omitempty"` // Romaji matches by dictionary type (j, n)
+
+	// Pinyin matches (when the key is a toneless, numbered or tone-marked pinyin reading of the entry)
+	Y map[string][]int64 `json:"y,omitempty"` // Pinyin matches by dictionary type (c, w)
+
+	// Contained-in positions (where the key appears within the entry's forms), parallel to C
+	P map[string][]Position `json:"p,omitempty"` // Position flags for each contained-in match (j, n, w)
+
+	// Matched forms (which written form of the entry the key is), parallel to E
+	F map[string][]int `json:"f,omitempty"` // Index into the word's Forms for each exact match (j)
+
+	// Alias links (when the key is a variant of other keys with entries, e.g. 国 → 國)
+	A []string `json:"a,omitempty"` // Variant keys to follow for variant matches
+
+	// Component links (when the key is a component of other characters, e.g. 氵 → 海)
+	K []string `json:"k,omitempty"` // Characters containing the key, fewest strokes first
+
+	// Variant matches, filled in by lookups that follow the alias links; never written to index files
+	X map[string][]int64 `json:"x,omitempty"` // Exact matches of variant keys by dictionary type
+
+	// postings is the set of IDs in each posting list while the index is built
+	postings map[postingKey]int
+}
+
+// MatchedForm returns the index of the written form an exact match was found under,
+// for looking up the form's readings and senses in jmdict.Word.Forms
+func (e *IndexEntry) MatchedForm(dictType string, id int64) (int, bool) {
+	forms := e.F[dictType]
+	for i, existingID := range e.E[dictType] {
+		if existingID == id && i < len(forms) {
+			return forms[i], true
+		}
+	}
+	return 0, false
+}
+
+// Position records where a contained-in key appears within an entry's forms.
+// A key can appear in several places, so positions are combined as flags.
+type Position int
+
+const (
+	PositionStart  Position = 1 << iota // First character of a form (日 in 日本)
+	PositionMiddle                      // Neither first nor last (本 in 日本語)
+	PositionEnd                         // Last character of a form (本 in 日本)
+)
+
+// ContainedPosition returns where a contained-in match contains the key. Index
+// files written before positions were recorded have none, and report false.
+func (e *IndexEntry) ContainedPosition(dictType string, id int64) (Position, bool) {
+	positions := e.P[dictType]
+	for i, existingID := range e.C[dictType] {
+		if existingID == id && i < len(positions) {
+			return positions[i], true
+		}
+	}
+	return 0, false
+}
+
+// ContainedAt returns the contained-in matches whose forms contain the key at any of
+// the given positions, by dictionary type: ContainedAt(PositionStart) lists the words
+// starting with the key. C remains the unfiltered list.
+func (e *IndexEntry) ContainedAt(pos Position) map[string][]int64 {
+	result := make(map[string][]int64)
+	for dictType, ids := range e.C {
+		positions := e.P[dictType]
+		for i, id := range ids {
+			if i < len(positions) && positions[i]&pos != 0 {
+				result[dictType] = append(result[dictType], id)
+			}
+		}
+	}
+	return result
 }
 
 // IndexProcessor processes dictionary entries and builds an index
@@ -544,6 +621,9 @@ func (p *IndexProcessor) WriteToFiles() error {
 // optimizeIndexEntry optimizes an index entry to reduce size
 // It removes empty arrays and ensures the entry is as small as possible
 func optimizeIndexEntry(entry *IndexEntry) {
+	// The posting sets are only needed while the index is built
+	entry.postings = nil
+
 	// Remove empty dictionary types from exact matches
 	if entry.E != nil {
 		for dictType, ids := range entry.E {
@@ -567,6 +647,66 @@ func optimizeIndexEntry(entry *IndexEntry) {
 			entry.C = nil
 		}
 	}
+
+	// Remove empty dictionary types from n-gram matches
+	if entry.G != nil {
+		for dictType, ids := range entry.G {
+			if len(ids) == 0 {
+				delete(entry.G, dictType)
+			}
+		}
+		if len(entry.G) == 0 {
+			entry.G = nil
+		}
+	}
+
+	// Remove empty dictionary types from romaji matches
+	if entry.R != nil {
+		for dictType, ids := range entry.R {
+			if len(ids) == 0 {
+				delete(entry.R, dictType)
+			}
+		}
+		if len(entry.R) == 0 {
+			entry.R = nil
+		}
+	}
+
+	// Remove empty dictionary types from pinyin matches
+	if entry.Y != nil {
+		for dictType, ids := range entry.Y {
+			if len(ids) == 0 {
+				delete(entry.Y, dictType)
+			}
+		}
+		if len(entry.Y) == 0 {
+			entry.Y = nil
+		}
+	}
+
+	// Remove empty dictionary types from contained-in positions
+	if entry.P != nil {
+		for dictType, positions := range entry.P {
+			if len(positions) == 0 {
+				delete(entry.P, dictType)
+			}
+		}
+		if len(entry.P) == 0 {
+			entry.P = nil
+		}
+	}
+
+	// Remove empty dictionary types from matched forms
+	if entry.F != nil {
+		for dictType, forms := range entry.F {
+			if len(forms) == 0 {
+				delete(entry.F, dictType)
+			}
+		}
+		if len(entry.F) == 0 {
+			entry.F = nil
+		}
+	}
 }
 
 // writeCompressedJSON writes an object to a Brotli-compressed JSON file
@@ -622,6 +762,41 @@ func isHanCharacter(char string) bool {
 	return false
 }
 
+// containedChars returns the Han characters of a set of forms, each once in order of
+// appearance, with the positions they appear at. A character that is a whole form
+// is at both the start and the end.
+func containedChars(forms []string) ([]string, map[string]Position) {
+	var chars []string
+	positions := make(map[string]Position)
+	for _, form := range forms {
+		runes := []rune(form)
+		for i, r := range runes {
+			char := string(r)
+			// Skip non-CJK characters
+			if !isHanCharacter(char) {
+				continue
+			}
+
+			var pos Position
+			if i == 0 {
+				pos |= PositionStart
+			}
+			if i == len(runes)-1 {
+				pos |= PositionEnd
+			}
+			if pos == 0 {
+				pos = PositionMiddle
+			}
+
+			if _, ok := positions[char]; !ok {
+				chars = append(chars, char)
+			}
+			positions[char] |= pos
+		}
+	}
+	return chars, positions
+}
+
 // removeDuplicates removes duplicate strings from a slice
 func removeDuplicates(slice []string) []string {
 	keys := make(map[string]bool)
@@ -0,0 +1,180 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"unicode"
+
+	"kiokun-go/dictionaries/chinese_words"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/jmdict"
+)
+
+// NgramConfig configures the n-gram contained-in index (the G section), which maps
+// multi-character substrings such as 日本 to the entries containing them (日本語, 北日本)
+type NgramConfig struct {
+	MinLength   int  // Shortest substring indexed, at least 2
+	MaxLength   int  // Longest substring indexed; 0 disables the n-gram index
+	IncludeKana bool // Also index substrings containing kana, not only Han characters
+	MaxPostings int  // Most entries kept per key and dictionary type, best ranked first; 0 keeps all
+}
+
+// DefaultNgramConfig returns the n-gram settings used by the -ngrams flag
+func DefaultNgramConfig() NgramConfig {
+	return NgramConfig{MinLength: 2, MaxLength: 4, MaxPostings: 100}
+}
+
+// Validate checks that the lengths of a configuration make sense
+func (c NgramConfig) Validate() error {
+	if c.MinLength < 2 {
+		return fmt.Errorf("n-gram minimum length must be at least 2, got %d", c.MinLength)
+	}
+	if c.MaxLength < c.MinLength {
+		return fmt.Errorf("n-gram maximum length %d is less than the minimum %d", c.MaxLength, c.MinLength)
+	}
+	if c.MaxPostings < 0 {
+		return fmt.Errorf("n-gram posting limit must not be negative, got %d", c.MaxPostings)
+	}
+	return nil
+}
+
+// ngramRank orders the entries under an n-gram key: common entries first, then
+// entries with shorter forms, which are usually the more basic words
+type ngramRank struct {
+	common bool
+	length int
+}
+
+// less reports whether an entry ranked r comes before one ranked o
+func (r ngramRank) less(o ngramRank) bool {
+	if r.common != o.common {
+		return r.common
+	}
+	return r.length < o.length
+}
+
+// SetNgrams enables the n-gram contained-in index with the given settings
+func (p *ShardedIndexProcessor) SetNgrams(config NgramConfig) {
+	p.ngrams = config
+	p.ngramRanks = make(map[string]map[int64]ngramRank)
+}
+
+// ngramKeys returns the substrings of an entry's forms to index, each once. Whole
+// forms are left out, since they are exact matches.
+func ngramKeys(forms []string, config NgramConfig) []string {
+	isForm := make(map[string]bool, len(forms))
+	for _, form := range forms {
+		isForm[form] = true
+	}
+
+	var keys []string
+	seen := make(map[string]bool)
+	for _, form := range forms {
+		runes := []rune(form)
+		for start := range runes {
+			for end := start + config.MinLength; end <= len(runes) && end-start <= config.MaxLength; end++ {
+				key := string(runes[start:end])
+				if !isNgram(key, config.IncludeKana) {
+					// Longer substrings from this start contain the same character
+					break
+				}
+				if isForm[key] || seen[key] {
+					continue
+				}
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// isNgram reports whether every character of a substring can be indexed
+func isNgram(key string, includeKana bool) bool {
+	for _, r := range key {
+		if !isNgramRune(r, includeKana) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNgramRune reports whether a character can be part of an indexed substring
+func isNgramRune(r rune, includeKana bool) bool {
+	if isHanCharacter(string(r)) {
+		return true
+	}
+	return includeKana && (unicode.In(r, unicode.Hiragana, unicode.Katakana) || r == 'ー')
+}
+
+// addNgrams indexes an entry under the substrings of its forms and remembers its rank.
+// Each key lives in its own shard rather than the entry's, so its matches form one
+// ranked list however the entries are sharded. The caller must hold p.mu.
+func (p *ShardedIndexProcessor) addNgrams(entry common.Entry, dictType string, id int64, forms []string) {
+	keys := ngramKeys(forms, p.ngrams)
+	if len(keys) == 0 {
+		return
+	}
+
+	for _, key := range keys {
+		indexEntry := p.getIndexEntry(GetShardTypeForText(key), key)
+		indexEntry.addPosting(sectionNgram, &indexEntry.G, dictType, id)
+	}
+
+	rank := ngramRank{common: isCommonEntry(entry), length: len([]rune(forms[0]))}
+	for _, form := range forms[1:] {
+		if n := len([]rune(form)); n < rank.length {
+			rank.length = n
+		}
+	}
+	if p.ngramRanks[dictType] == nil {
+		p.ngramRanks[dictType] = make(map[int64]ngramRank)
+	}
+	p.ngramRanks[dictType][id] = rank
+}
+
+// rankNgrams sorts every n-gram posting list by rank and trims it to the configured size
+func (p *ShardedIndexProcessor) rankNgrams() {
+	if p.ngrams.MaxLength == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, index := range p.indexes {
+		for _, entry := range index {
+			for dictType, ids := range entry.G {
+				ranks := p.ngramRanks[dictType]
+				sort.SliceStable(ids, func(i, j int) bool {
+					return ranks[ids[i]].less(ranks[ids[j]])
+				})
+				if p.ngrams.MaxPostings > 0 && len(ids) > p.ngrams.MaxPostings {
+					ids = ids[:p.ngrams.MaxPostings]
+				}
+				entry.G[dictType] = ids
+			}
+		}
+	}
+}
+
+// isCommonEntry reports whether an entry is a common word: a JMdict word with a
+// common form, or a Chinese word in the HSK lists
+func isCommonEntry(entry common.Entry) bool {
+	switch e := entry.(type) {
+	case jmdict.Word:
+		for _, k := range e.Kanji {
+			if k.Common {
+				return true
+			}
+		}
+		for _, k := range e.Kana {
+			if k.Common {
+				return true
+			}
+		}
+	case chinese_words.ChineseWordEntry:
+		return e.HskLevel > 0
+	}
+	return false
+}
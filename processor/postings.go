@@ -0,0 +1,133 @@
+package processor
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"sort"
+)
+
+// CompactVersion is the posting encoding version written in the Z field of compact index files
+const CompactVersion = 1
+
+// section identifies a posting list section of an index entry
+type section byte
+
+const (
+	sectionExact section = iota
+	sectionContained
+	sectionRomaji
+	sectionPinyin
+	sectionNgram
+)
+
+// postingKey identifies one ID in one posting list of an index entry
+type postingKey struct {
+	section  section
+	dictType string
+	id       int64
+}
+
+// addPosting appends an ID to a section's posting list for a dictionary type unless
+// it is already there, using the entry's posting set instead of scanning the list.
+// It returns the ID's position in the list and whether it was added.
+func (e *IndexEntry) addPosting(s section, lists *map[string][]int64, dictType string, id int64) (int, bool) {
+	key := postingKey{section: s, dictType: dictType, id: id}
+	if i, ok := e.postings[key]; ok {
+		return i, false
+	}
+
+	if e.postings == nil {
+		e.postings = make(map[postingKey]int)
+	}
+	if *lists == nil {
+		*lists = make(map[string][]int64)
+	}
+	i := len((*lists)[dictType])
+	(*lists)[dictType] = append((*lists)[dictType], id)
+	e.postings[key] = i
+	return i, true
+}
+
+// CompactIndexEntry is the compact form of an IndexEntry written with
+// SetCompactPostings. Posting lists are encoded with EncodePostings; E, C, R and Y
+// are sorted by ID, with F and P reordered to stay parallel to E and C. G keeps its
+// ranked order.
+type CompactIndexEntry struct {
+	Z int                   `json:"z"` // Encoding version, CompactVersion
+	E map[string]string     `json:"e,omitempty"`
+	C map[string]string     `json:"c,omitempty"`
+	P map[string][]Position `json:"p,omitempty"`
+	G map[string]string     `json:"g,omitempty"`
+	R map[string]string     `json:"r,omitempty"`
+	Y map[string]string     `json:"y,omitempty"`
+	F map[string][]int      `json:"f,omitempty"`
+	A []string              `json:"a,omitempty"`
+	K []string              `json:"k,omitempty"`
+}
+
+// EncodePostings encodes a posting list as base64 varints: the first ID, then the
+// difference from each ID to the next, all zigzag encoded so unsorted lists and
+// negative hashed IDs round-trip. Sorted lists encode to small deltas.
+func EncodePostings(ids []int64) string {
+	buf := make([]byte, 0, len(ids)*2)
+	var prev int64
+	for _, id := range ids {
+		delta := id - prev
+		buf = binary.AppendUvarint(buf, uint64(delta<<1)^uint64(delta>>63))
+		prev = id
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// compactIndexEntry converts an index entry to its compact form
+func compactIndexEntry(entry *IndexEntry) *CompactIndexEntry {
+	c := &CompactIndexEntry{Z: CompactVersion, A: entry.A, K: entry.K}
+	c.E, c.F = encodeSorted(entry.E, entry.F)
+	c.C, c.P = encodeSorted(entry.C, entry.P)
+	c.R, _ = encodeSorted(entry.R, map[string][]int(nil))
+	c.Y, _ = encodeSorted(entry.Y, map[string][]int(nil))
+	if len(entry.G) > 0 {
+		c.G = make(map[string]string, len(entry.G))
+		for dictType, ids := range entry.G {
+			c.G[dictType] = EncodePostings(ids)
+		}
+	}
+	return c
+}
+
+// encodeSorted sorts each posting list by ID, reorders its parallel list to match and
+// encodes the IDs
+func encodeSorted[T any](lists map[string][]int64, parallel map[string][]T) (map[string]string, map[string][]T) {
+	if len(lists) == 0 {
+		return nil, nil
+	}
+
+	encoded := make(map[string]string, len(lists))
+	var reordered map[string][]T
+	for dictType, ids := range lists {
+		order := make([]int, len(ids))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool { return ids[order[i]] < ids[order[j]] })
+
+		sorted := make([]int64, len(ids))
+		for i, k := range order {
+			sorted[i] = ids[k]
+		}
+		encoded[dictType] = EncodePostings(sorted)
+
+		values := parallel[dictType]
+		if len(values) != len(ids) {
+			continue
+		}
+		if reordered == nil {
+			reordered = make(map[string][]T)
+		}
+		reordered[dictType] = make([]T, len(values))
+		for i, k := range order {
+			reordered[dictType][i] = values[k]
+		}
+	}
+	return encoded, reordered
+}
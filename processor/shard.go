@@ -55,9 +55,14 @@ func GetShardType(entry common.Entry) ShardType {
 		primaryText = entry.GetID()
 	}
 
+	return GetShardTypeForText(primaryText)
+}
+
+// GetShardTypeForText determines which shard a text belongs to
+func GetShardTypeForText(text string) ShardType {
 	// Check if it contains only Han characters
-	isHan := isHanOnly(primaryText)
-	charCount := len([]rune(primaryText))
+	isHan := isHanOnly(text)
+	charCount := len([]rune(text))
 
 	if !isHan {
 		return ShardNonHan
@@ -5,16 +5,28 @@ import (
 	"hash/fnv"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
 
+	"kiokun-go/cognates"
+	"kiokun-go/components"
+	"kiokun-go/conjugation"
 	"kiokun-go/dictionaries/chinese_chars"
 	"kiokun-go/dictionaries/chinese_words"
 	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/ids"
 	"kiokun-go/dictionaries/jmdict"
 	"kiokun-go/dictionaries/jmnedict"
 	"kiokun-go/dictionaries/kanjidic"
+	"kiokun-go/dictionaries/kanjivg"
+	"kiokun-go/dictionaries/kradfile"
+	"kiokun-go/dictionaries/makemeahanzi"
+	"kiokun-go/furigana"
+	"kiokun-go/pinyin"
+	"kiokun-go/romaji"
+	"kiokun-go/variants"
 )
 
 // ShardedIndexProcessor processes dictionary entries and builds sharded indexes
@@ -30,7 +42,21 @@ type ShardedIndexProcessor struct {
 	indexes          map[ShardType]map[string]*IndexEntry
 	writtenEntries   map[ShardType]map[string]bool
 	fileWriters      int
-	idsMap           map[string]string // Map of character to IDS
+	idsMap           map[string]ids.IDSEntry           // Map of character to its IDS entry
+	kanjiReadings    furigana.Readings                 // Kanji readings used for furigana alignment
+	variants         *variants.Graph                   // Character variants used for alias links
+	cognates         map[string][]common.Cognate       // Cognate links by dictionary type and original ID
+	components       *components.Index                 // Character components used for component links
+	compactPostings  bool                              // Write posting lists with EncodePostings
+	consolidateKeys  bool                              // Write every key to its own shard instead of the entry's
+	ngrams           NgramConfig                       // N-gram contained-in index settings; disabled when MaxLength is 0
+	ngramRanks       map[string]map[int64]ngramRank    // Ranks of entries under n-gram keys by dictionary type
+	browse           map[browseKey][]browseEntry       // Kanjidic characters listed by SKIP, four-corner and radical codes
+	radicals         *kradfile.Table                   // KRADFILE radicals for Kanjidic entries and the radical picker
+	strokes          map[string]kanjivg.Character      // KanjiVG stroke data written next to single character entries
+	strokeMismatches map[string]string                 // Characters whose stroke count differs from KanjiVG
+	hanzi            map[string]makemeahanzi.Character // Make Me a Hanzi data for Chinese character entries
+	hanziMismatches  map[string]string                 // Characters whose Make Me a Hanzi decomposition differs from IDS
 	mu               sync.Mutex
 }
 
@@ -49,7 +75,7 @@ func NewShardedIndexProcessor(baseDir string, fileWriters int) (*ShardedIndexPro
 		indexes:          make(map[ShardType]map[string]*IndexEntry),
 		writtenEntries:   make(map[ShardType]map[string]bool),
 		fileWriters:      fileWriters,
-		idsMap:           make(map[string]string),
+		idsMap:           make(map[string]ids.IDSEntry),
 	}
 
 	// Initialize indexes and writtenEntries for each shard
@@ -66,11 +92,82 @@ func NewShardedIndexProcessor(baseDir string, fileWriters int) (*ShardedIndexPro
 	return p, nil
 }
 
-// SetIDSMap sets the IDS map for the processor
-func (p *ShardedIndexProcessor) SetIDSMap(idsMap map[string]string) {
+// SetIDSMap sets the IDS map for the processor. Kanjidic entries get the J (Japan)
+// decomposition of their character and Chinese character entries the G or T one.
+func (p *ShardedIndexProcessor) SetIDSMap(idsMap map[string]ids.IDSEntry) {
 	p.idsMap = idsMap
 }
 
+// SetKanjiReadings sets the kanji readings used to align furigana for JMdict words
+func (p *ShardedIndexProcessor) SetKanjiReadings(readings furigana.Readings) {
+	p.kanjiReadings = readings
+}
+
+// SetVariants sets the character variant graph used to add alias links to the index
+func (p *ShardedIndexProcessor) SetVariants(graph *variants.Graph) {
+	p.variants = graph
+}
+
+// SetCognates sets the Japanese/Chinese cognate pairs linked from both entries of each pair
+func (p *ShardedIndexProcessor) SetCognates(pairs []cognates.Pair) {
+	p.cognates = make(map[string][]common.Cognate)
+	for _, pair := range pairs {
+		wordID := parseShardedID(fmt.Sprintf("%d%s", GetShardType(pair.Word), pair.Word.ID))
+		chineseID := parseShardedID(fmt.Sprintf("%d%s", GetShardType(pair.Chinese), pair.Chinese.ID))
+
+		wordKey := "j" + pair.Word.ID
+		p.cognates[wordKey] = append(p.cognates[wordKey], common.Cognate{ID: chineseID, Text: pair.Text, Overlap: pair.Overlap})
+		chineseKey := "w" + pair.Chinese.ID
+		p.cognates[chineseKey] = append(p.cognates[chineseKey], common.Cognate{ID: wordID, Text: pair.Form, Overlap: pair.Overlap})
+	}
+}
+
+// SetConsolidateKeys makes every exact and contained-in key live only in the shard
+// GetShardTypeForText assigns to the key itself, so a lookup reads one index file.
+// Entry files stay in their own shards; sharded IDs in the index point across shards.
+// By default a key is written to the shard of each entry it matches.
+func (p *ShardedIndexProcessor) SetConsolidateKeys(enabled bool) {
+	p.consolidateKeys = enabled
+}
+
+// SetCompactPostings writes index files as CompactIndexEntry, with posting lists
+// encoded as base64 varint deltas instead of decimal arrays. Clients must decode
+// them, as lookup.Dictionary does.
+func (p *ShardedIndexProcessor) SetCompactPostings(enabled bool) {
+	p.compactPostings = enabled
+}
+
+// keyShard returns the shard whose index holds a key of an entry in entryShard
+func (p *ShardedIndexProcessor) keyShard(entryShard ShardType, key string) ShardType {
+	if p.consolidateKeys {
+		return GetShardTypeForText(key)
+	}
+	return entryShard
+}
+
+// parseShardedID converts a sharded ID to the int64 used in the index, hashing IDs
+// that are not numbers
+func parseShardedID(id string) int64 {
+	idInt, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		h := fnv.New64a()
+		h.Write([]byte(id))
+		idInt = int64(h.Sum64())
+	}
+	return idInt
+}
+
+// SetComponents sets the component index used to add component links to the index
+func (p *ShardedIndexProcessor) SetComponents(index *components.Index) {
+	p.components = index
+}
+
+// SetRadicals sets the KRADFILE table whose radicals are added to Kanjidic entries and
+// written as the radical picker table
+func (p *ShardedIndexProcessor) SetRadicals(table *kradfile.Table) {
+	p.radicals = table
+}
+
 // createDirectories creates the necessary output directories for each shard
 func (p *ShardedIndexProcessor) createDirectories() error {
 	// Create the base directory if it doesn't exist
@@ -148,25 +245,31 @@ func (p *ShardedIndexProcessor) processEntry(entry common.Entry) error {
 	id := fmt.Sprintf("%d%s", shardType, originalID)
 
 	// Convert string ID to int64 where possible
-	idInt, err := strconv.ParseInt(id, 10, 64)
-	if err != nil {
-		// If ID is not a number, use a hash of the string as the ID
-		h := fnv.New64a()
-		h.Write([]byte(id))
-		idInt = int64(h.Sum64())
-	}
+	idInt := parseShardedID(id)
+
+	// Determine exact, contained-in, romaji and pinyin matches based on entry type
+	var exactMatches, containedMatches, romajiMatches, pinyinMatches []string
 
-	// Determine exact matches and contained-in matches based on entry type
-	var exactMatches, containedMatches []string
+	// containedPositions maps a contained-in match to where it appears in the entry's forms
+	var containedPositions map[string]Position
+
+	// formIndexes maps a JMdict exact match to its position in the word's form matrix
+	var formIndexes map[string]int
 
 	switch e := entry.(type) {
 	case jmdict.Word:
-		// For JMdict words, exact matches are the kanji and kana forms
+		// For JMdict words, exact matches are the kanji and kana forms,
+		// in the same order as the form matrix
 		for _, k := range e.Kanji {
 			exactMatches = append(exactMatches, k.Text)
 		}
 		for _, k := range e.Kana {
 			exactMatches = append(exactMatches, k.Text)
+			romajiMatches = append(romajiMatches, romaji.IndexKeys(k.Text)...)
+		}
+		formIndexes = make(map[string]int, len(exactMatches))
+		for i := len(exactMatches) - 1; i >= 0; i-- {
+			formIndexes[exactMatches[i]] = i
 		}
 
 		// If no forms, use ID as exact match
@@ -174,37 +277,26 @@ func (p *ShardedIndexProcessor) processEntry(entry common.Entry) error {
 			exactMatches = append(exactMatches, e.ID)
 		}
 
-		// For multi-character entries, each character is a contained-in match
-		for _, form := range exactMatches {
-			for _, char := range form {
-				// Skip non-CJK characters
-				if !isHanCharacter(string(char)) {
-					continue
-				}
-				containedMatches = append(containedMatches, string(char))
-			}
-		}
+		// For multi-character entries, each character is a contained-in match,
+		// recorded with where it appears in the forms
+		containedMatches, containedPositions = containedChars(exactMatches)
 
 	case jmnedict.Name:
 		// For JMNedict names, exact matches are the kanji and reading forms
 		exactMatches = append(exactMatches, e.Kanji...)
 		exactMatches = append(exactMatches, e.Reading...)
+		for _, reading := range e.Reading {
+			romajiMatches = append(romajiMatches, romaji.IndexKeys(reading)...)
+		}
 
 		// If no forms, use ID as exact match
 		if len(exactMatches) == 0 {
 			exactMatches = append(exactMatches, e.ID)
 		}
 
-		// For multi-character entries, each character is a contained-in match
-		for _, form := range exactMatches {
-			for _, char := range form {
-				// Skip non-CJK characters
-				if !isHanCharacter(string(char)) {
-					continue
-				}
-				containedMatches = append(containedMatches, string(char))
-			}
-		}
+		// For multi-character entries, each character is a contained-in match,
+		// recorded with where it appears in the forms
+		containedMatches, containedPositions = containedChars(exactMatches)
 
 	case kanjidic.Kanji:
 		// For Kanjidic entries, the character is an exact match
@@ -218,6 +310,9 @@ func (p *ShardedIndexProcessor) processEntry(entry common.Entry) error {
 		if e.Simplified != e.Traditional {
 			exactMatches = append(exactMatches, e.Simplified)
 		}
+		for _, reading := range e.Pinyin {
+			pinyinMatches = append(pinyinMatches, pinyin.IndexKeys(reading)...)
+		}
 
 	case chinese_words.ChineseWordEntry:
 		// For Chinese word entries, the traditional and simplified forms are exact matches
@@ -227,19 +322,15 @@ func (p *ShardedIndexProcessor) processEntry(entry common.Entry) error {
 			exactMatches = append(exactMatches, e.Simplified)
 
 		}
-
-		// For multi-character entries, each character is a contained-in match
-		for _, form := range exactMatches {
-			for _, char := range form {
-				// Skip non-CJK characters
-				if !isHanCharacter(string(char)) {
-					continue
-				}
-				containedMatches = append(containedMatches, string(char))
-
-			}
+		// Index the reading of every item, not only the legacy pinyin list
+		for _, reading := range e.Readings() {
+			pinyinMatches = append(pinyinMatches, pinyin.IndexKeys(reading)...)
 		}
 
+		// For multi-character entries, each character is a contained-in match,
+		// recorded with where it appears in the forms
+		containedMatches, containedPositions = containedChars(exactMatches)
+
 	default:
 		// For unknown entry types, use ID as exact match
 		exactMatches = append(exactMatches, entry.GetID())
@@ -251,6 +342,12 @@ func (p *ShardedIndexProcessor) processEntry(entry common.Entry) error {
 	// Remove exact matches from contained-in matches
 	containedMatches = removeExactMatches(containedMatches, exactMatches)
 
+	// Remove duplicates from romajiMatches
+	romajiMatches = removeDuplicates(romajiMatches)
+
+	// Remove duplicates from pinyinMatches
+	pinyinMatches = removeDuplicates(pinyinMatches)
+
 	// Get the dictionary type code
 	var dictType string
 	switch entry.(type) {
@@ -271,34 +368,18 @@ func (p *ShardedIndexProcessor) processEntry(entry common.Entry) error {
 
 	// Process exact matches
 	for _, key := range exactMatches {
-
 		// Get or create the index entry
-		indexEntry, ok := p.indexes[shardType][key]
-		if !ok {
-			indexEntry = &IndexEntry{
-				E: make(map[string][]int64),
-				C: make(map[string][]int64),
-			}
-			p.indexes[shardType][key] = indexEntry
-
-		}
-
-		// Initialize maps if nil
-		if indexEntry.E == nil {
-			indexEntry.E = make(map[string][]int64)
-		}
+		indexEntry := p.getIndexEntry(p.keyShard(shardType, key), key)
 
 		// Add to the exact match list
-		exists := false
-		for _, existingID := range indexEntry.E[dictType] {
-			if existingID == idInt {
-				exists = true
-				break
+		if _, added := indexEntry.addPosting(sectionExact, &indexEntry.E, dictType, idInt); added {
+			// Record which form matched so lookups can show only that form's senses
+			if formIndex, ok := formIndexes[key]; ok {
+				if indexEntry.F == nil {
+					indexEntry.F = make(map[string][]int)
+				}
+				indexEntry.F[dictType] = append(indexEntry.F[dictType], formIndex)
 			}
-		}
-		if !exists {
-			indexEntry.E[dictType] = append(indexEntry.E[dictType], idInt)
-
 		} else if key == "日本" {
 			// Debug: Print when ID already exists in exact match list for "日本"
 			fmt.Printf("DEBUG: ID %d already exists in exact match list for key '日本' with dictionary type '%s'\n", idInt, dictType)
@@ -308,52 +389,95 @@ func (p *ShardedIndexProcessor) processEntry(entry common.Entry) error {
 	// Process contained-in matches
 	for _, key := range containedMatches {
 		// Get or create the index entry
-		indexEntry, ok := p.indexes[shardType][key]
-		if !ok {
-			indexEntry = &IndexEntry{
-				E: make(map[string][]int64),
-				C: make(map[string][]int64),
+		indexEntry := p.getIndexEntry(p.keyShard(shardType, key), key)
+
+		// Add to the contained-in match list, keeping positions parallel to it
+		i, added := indexEntry.addPosting(sectionContained, &indexEntry.C, dictType, idInt)
+		if added {
+			if indexEntry.P == nil {
+				indexEntry.P = make(map[string][]Position)
 			}
-			p.indexes[shardType][key] = indexEntry
+			indexEntry.P[dictType] = append(indexEntry.P[dictType], containedPositions[key])
+		} else if i < len(indexEntry.P[dictType]) {
+			indexEntry.P[dictType][i] |= containedPositions[key]
 		}
+	}
 
-		// Initialize maps if nil
-		if indexEntry.C == nil {
-			indexEntry.C = make(map[string][]int64)
-		}
+	// Process romaji matches
+	// Romaji keys are always non-Han, so they live in the non-Han shard and point back to the entry's sharded ID
+	for _, key := range romajiMatches {
+		indexEntry := p.getIndexEntry(ShardNonHan, key)
+		indexEntry.addPosting(sectionRomaji, &indexEntry.R, dictType, idInt)
+	}
 
-		// Add to the contained-in match list
-		exists := false
-		for _, existingID := range indexEntry.C[dictType] {
-			if existingID == idInt {
-				exists = true
-				break
-			}
-		}
-		if !exists {
-			indexEntry.C[dictType] = append(indexEntry.C[dictType], idInt)
-		}
+	// Process pinyin matches
+	// Like romaji keys, pinyin keys are non-Han and live in the non-Han shard
+	for _, key := range pinyinMatches {
+		indexEntry := p.getIndexEntry(ShardNonHan, key)
+		indexEntry.addPosting(sectionPinyin, &indexEntry.Y, dictType, idInt)
+	}
+
+	// Process n-gram matches for multi-character word and name entries
+	if p.ngrams.MaxLength > 0 && (dictType == "j" || dictType == "n" || dictType == "w") {
+		p.addNgrams(entry, dictType, idInt, exactMatches)
+	}
+
+	// List kanji in the browse files of their lookup codes
+	if k, ok := entry.(kanjidic.Kanji); ok {
+		p.addBrowseKeys(shardType, k, idInt)
 	}
 
 	p.mu.Unlock()
 
-	// Add IDS data to single Han character entries
+	// Attach derived data: IDS for single Han character entries, conjugation tables, furigana and form matrices for words,
+	// and cognate links between Japanese and Chinese words
 	var updatedEntry common.Entry
 	switch e := entry.(type) {
+	case jmdict.Word:
+		// For JMdict words, precompute conjugation tables for verbs and adjectives
+		// and resolve which readings and senses belong to each written form
+		entryCopy := e
+		entryCopy.Conjugations = conjugation.ForWord(e)
+		entryCopy.Forms = e.FormMatrix()
+
+		// Align kanji forms with their readings once kanji readings are available
+		if len(p.kanjiReadings) > 0 {
+			entryCopy.Furigana = furigana.ForWord(e, p.kanjiReadings)
+		}
+
+		// Link Chinese words written the same way
+		entryCopy.Cognates = p.cognates["j"+e.ID]
+
+		if len(entryCopy.Conjugations) > 0 || len(entryCopy.Furigana) > 0 || len(entryCopy.Forms) > 0 || len(entryCopy.Cognates) > 0 {
+			updatedEntry = entryCopy
+		}
 	case kanjidic.Kanji:
-		// For Kanjidic entries, add IDS data if available
-		if ids, ok := p.idsMap[e.Character]; ok {
-			// Create a copy of the entry with IDS data
-			entryCopy := e
-			entryCopy.IDS = ids
+		// For Kanjidic entries, add IDS data and KRADFILE radicals if available
+		entryCopy := e
+		if idsEntry, ok := p.idsMap[e.Character]; ok {
+			// Use the Japanese IDS
+			entryCopy.IDS = idsEntry.ForRegion("J")
+		}
+		if p.radicals != nil {
+			entryCopy.Parts = p.radicals.Components(e.Character)
+		}
+		if entryCopy.IDS != e.IDS || len(entryCopy.Parts) > 0 {
 			updatedEntry = entryCopy
 		}
 	case chinese_chars.ChineseCharEntry:
-		// For Chinese character entries, add IDS data if available
-		if ids, ok := p.idsMap[e.Traditional]; ok {
-			// Create a copy of the entry with IDS data
+		// For Chinese character entries, add IDS data and Make Me a Hanzi data if available
+		entryCopy := e
+		if idsEntry, ok := p.idsMap[e.Traditional]; ok {
+			// Use the mainland or Taiwan IDS
+			entryCopy.IDS = idsEntry.ForRegion("G", "T")
+		}
+		p.addHanzi(&entryCopy)
+		updatedEntry = entryCopy
+	case chinese_words.ChineseWordEntry:
+		// For Chinese word entries, link JMdict words written the same way
+		if links, ok := p.cognates["w"+e.ID]; ok {
 			entryCopy := e
-			entryCopy.IDS = ids
+			entryCopy.Cognates = links
 			updatedEntry = entryCopy
 		}
 	}
@@ -378,6 +502,20 @@ func (p *ShardedIndexProcessor) processEntry(entry common.Entry) error {
 	return nil
 }
 
+// getIndexEntry returns the index entry for a key in a shard, creating it if needed
+// The caller must hold p.mu
+func (p *ShardedIndexProcessor) getIndexEntry(shardType ShardType, key string) *IndexEntry {
+	indexEntry, ok := p.indexes[shardType][key]
+	if !ok {
+		indexEntry = &IndexEntry{
+			E: make(map[string][]int64),
+			C: make(map[string][]int64),
+		}
+		p.indexes[shardType][key] = indexEntry
+	}
+	return indexEntry
+}
+
 // writeEntryToFile writes an entry to its dictionary file in the appropriate shard
 func (p *ShardedIndexProcessor) writeEntryToFile(entry common.Entry, shardType ShardType) error {
 	var dir string
@@ -417,11 +555,157 @@ func (p *ShardedIndexProcessor) writeEntryToFile(entry common.Entry, shardType S
 		fmt.Printf("🌞 FINAL_FILE: Writing '日' entry to file: %s\n", filePath)
 	}
 
-	return writeCompressedJSON(filePath, entry)
+	if err := writeCompressedJSON(filePath, entry); err != nil {
+		return err
+	}
+
+	// Write the stroke order of single character entries to companion files
+	if err := p.writeStrokes(entry, filepath.Join(dir, shardedID+StrokesSuffix)); err != nil {
+		return err
+	}
+	return p.writeGraphics(entry, filepath.Join(dir, shardedID+GraphicsSuffix))
+}
+
+// addVariantLinks links variant keys through one canonical key, so a lookup of 国
+// can reach entries written 國. Every key with variants links to its canonical key
+// (Graph.Canonical for characters, Graph.Normalize for words), and the canonical key
+// links back to the keys with exact matches, so a group of k keys needs 2(k-1) links
+// and a lookup follows at most two hops. Links live in the shard of the key itself,
+// creating an index entry for it if needed; 国家 is linked to 國家 even when no
+// entry is written 国家.
+func (p *ShardedIndexProcessor) addVariantLinks() {
+	if p.variants == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	hasExact := func(key string) bool {
+		for _, index := range p.indexes {
+			if entry, ok := index[key]; ok {
+				for _, ids := range entry.E {
+					if len(ids) > 0 {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+
+	links := 0
+	link := func(key, target string) {
+		indexEntry := p.getIndexEntry(GetShardTypeForText(key), key)
+		if !containsKey(indexEntry.A, target) {
+			indexEntry.A = append(indexEntry.A, target)
+			links++
+		}
+	}
+
+	// Group the characters of the graph and the words with exact matches by their
+	// canonical key
+	groups := make(map[string][]string)
+	for _, char := range p.variants.Characters() {
+		canonical := p.variants.Canonical(char)
+		groups[canonical] = append(groups[canonical], char)
+	}
+	seen := make(map[string]bool)
+	for _, index := range p.indexes {
+		for key := range index {
+			if seen[key] || len([]rune(key)) < 2 || !hasExact(key) {
+				continue
+			}
+			seen[key] = true
+			canonical := p.variants.Normalize(key)
+			if canonical != key {
+				groups[canonical] = append(groups[canonical], key)
+			}
+		}
+	}
+
+	canonicals := make([]string, 0, len(groups))
+	for canonical := range groups {
+		canonicals = append(canonicals, canonical)
+	}
+	sort.Strings(canonicals)
+
+	for _, canonical := range canonicals {
+		keys := groups[canonical]
+		sort.Strings(keys)
+
+		written := hasExact(canonical)
+		for _, key := range keys {
+			if key != canonical && hasExact(key) {
+				written = true
+			}
+		}
+		if !written {
+			continue
+		}
+
+		for _, key := range keys {
+			if key == canonical {
+				continue
+			}
+			link(key, canonical)
+			if hasExact(key) {
+				link(canonical, key)
+			}
+		}
+	}
+	fmt.Printf("Added %d variant links\n", links)
+}
+
+// addComponentLinks gives each component the characters containing it, so a client
+// can search by components by intersecting the lists of a few index files. The list
+// lives in the shard of the component itself, creating an index entry if needed.
+// Components without a code point are skipped, as they cannot be typed.
+func (p *ShardedIndexProcessor) addComponentLinks() {
+	if p.components == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	links := 0
+	for _, component := range p.components.All() {
+		if len([]rune(component)) != 1 {
+			continue
+		}
+		indexEntry := p.getIndexEntry(GetShardTypeForText(component), component)
+		indexEntry.K = p.components.Characters(component)
+		links++
+	}
+	fmt.Printf("Added component links for %d components\n", links)
+}
+
+// containsKey reports whether a list of keys contains a key
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
 }
 
 // WriteToFiles writes all index entries to files for each shard
 func (p *ShardedIndexProcessor) WriteToFiles() error {
+	// Link variant keys and components and rank n-gram matches before writing, once every entry has been indexed
+	p.addVariantLinks()
+	p.addComponentLinks()
+	p.rankNgrams()
+
+	// Write the browse files of Kanjidic lookup codes and the radical picker table
+	if err := p.writeBrowseFiles(); err != nil {
+		return err
+	}
+	if err := p.writeRadicals(); err != nil {
+		return err
+	}
+
 	// Count total files to write across all shards
 	totalFiles := 0
 	totalDictFiles := 0
@@ -483,7 +767,11 @@ func (p *ShardedIndexProcessor) WriteToFiles() error {
 			go func() {
 				for j := range jobs {
 					filename := filepath.Join(p.indexDirs[shardType], j.key+".json.br")
-					err := writeCompressedJSON(filename, j.entry)
+					var obj interface{} = j.entry
+					if p.compactPostings {
+						obj = compactIndexEntry(j.entry)
+					}
+					err := writeCompressedJSON(filename, obj)
 
 					mu.Lock()
 					completed++
@@ -519,6 +807,8 @@ func (p *ShardedIndexProcessor) WriteToFiles() error {
 
 	// Print statistics for all shards
 	p.printStatistics()
+	p.reportStrokeMismatches()
+	p.reportHanziMismatches()
 
 	return nil
 }
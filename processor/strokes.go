@@ -0,0 +1,73 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/kanjidic"
+	"kiokun-go/dictionaries/kanjivg"
+)
+
+// StrokesSuffix ends the name of the stroke order file written next to a Kanjidic or
+// Chinese character entry: d/<id>.s.json.br beside d/<id>.json.br
+const StrokesSuffix = ".s.json.br"
+
+// SetStrokes sets the KanjiVG stroke data written as companion files of Kanjidic and
+// Chinese character entries, keyed by character
+func (p *ShardedIndexProcessor) SetStrokes(characters map[string]kanjivg.Character) {
+	p.strokes = characters
+}
+
+// writeStrokes writes the stroke data of a single character entry to path, if KanjiVG
+// has the character, and records whether its stroke count agrees with the entry's
+func (p *ShardedIndexProcessor) writeStrokes(entry common.Entry, path string) error {
+	if len(p.strokes) == 0 {
+		return nil
+	}
+
+	var char string
+	var count int
+	switch e := entry.(type) {
+	case kanjidic.Kanji:
+		char, count = e.Character, e.Stroke
+	case chinese_chars.ChineseCharEntry:
+		char, count = e.Traditional, e.StrokeCount
+	default:
+		return nil
+	}
+
+	strokes, ok := p.strokes[char]
+	if !ok {
+		return nil
+	}
+	if count > 0 && count != len(strokes.Strokes) {
+		p.mu.Lock()
+		if p.strokeMismatches == nil {
+			p.strokeMismatches = make(map[string]string)
+		}
+		p.strokeMismatches[char] = fmt.Sprintf("%s %d/%d", char, count, len(strokes.Strokes))
+		p.mu.Unlock()
+	}
+	return writeCompressedJSON(path, strokes)
+}
+
+// reportStrokeMismatches prints the characters whose dictionary stroke count differs
+// from the number of KanjiVG strokes, as entry count/KanjiVG count
+func (p *ShardedIndexProcessor) reportStrokeMismatches() {
+	if len(p.strokeMismatches) == 0 {
+		return
+	}
+
+	mismatches := make([]string, 0, len(p.strokeMismatches))
+	for _, m := range p.strokeMismatches {
+		mismatches = append(mismatches, m)
+	}
+	sort.Strings(mismatches)
+	if len(mismatches) > 20 {
+		mismatches = append(mismatches[:20], "...")
+	}
+	fmt.Printf("Stroke counts differ from KanjiVG for %d characters: %s\n", len(p.strokeMismatches), strings.Join(mismatches, ", "))
+}
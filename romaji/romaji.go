@@ -0,0 +1,377 @@
+package romaji
+
+import (
+	"strings"
+	"unicode"
+)
+
+// System selects the romanization system
+type System int
+
+const (
+	Hepburn System = iota // Modified Hepburn (しゃ → sha, つ → tsu)
+	Kunrei                // Kunrei-shiki (しゃ → sya, つ → tu)
+)
+
+// LongVowels selects how long vowels (おう, ああ, ー) are written
+type LongVowels int
+
+const (
+	Macrons LongVowels = iota // とうきょう → tōkyō (circumflexes for Kunrei: tôkyô)
+	Wapuro                    // とうきょう → toukyou, as typed on a keyboard
+	Plain                     // とうきょう → tokyo, long vowels collapsed
+)
+
+// Options configures kana to romaji conversion
+type Options struct {
+	System     System
+	LongVowels LongVowels
+}
+
+// hepburn maps single hiragana to modified Hepburn
+var hepburn = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa", 'ゕ': "ka", 'ゖ': "ke",
+}
+
+// hepburnDigraphs maps two-kana combinations (consonant + small kana) to modified Hepburn
+var hepburnDigraphs = map[string]string{
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"しゃ": "sha", "しゅ": "shu", "しょ": "sho", "しぇ": "she",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo", "じぇ": "je",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho", "ちぇ": "che",
+	"ぢゃ": "ja", "ぢゅ": "ju", "ぢょ": "jo",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo", "ふゅ": "fyu",
+	"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du", "でゅ": "dyu",
+	"つぁ": "tsa", "つぃ": "tsi", "つぇ": "tse", "つぉ": "tso",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+	"いぇ": "ye", "くぁ": "kwa", "ぐぁ": "gwa",
+}
+
+// kunreiOverrides replaces the Hepburn spellings that differ in Kunrei-shiki
+var kunreiOverrides = map[string]string{
+	"shi": "si", "chi": "ti", "tsu": "tu", "fu": "hu", "ji": "zi",
+	"sha": "sya", "shu": "syu", "sho": "syo",
+	"cha": "tya", "chu": "tyu", "cho": "tyo",
+	"ja": "zya", "ju": "zyu", "jo": "zyo",
+}
+
+// ToHiragana converts katakana in s to hiragana, leaving other characters untouched
+func ToHiragana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - 0x60
+		}
+		return r
+	}, s)
+}
+
+// ToRomaji converts kana (hiragana or katakana) to romaji.
+// Characters that are not kana are passed through unchanged.
+func ToRomaji(kana string, opts Options) string {
+	runes := []rune(ToHiragana(kana))
+
+	// First split into syllables so gemination and long vowels can look at neighbours
+	type syllable struct {
+		text string
+		kana bool // false for pass-through characters
+		long bool // vowel lengthened by ー
+	}
+	var syllables []syllable
+	geminate := false
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch r {
+		case 'っ':
+			geminate = true
+			continue
+		case 'ー':
+			if n := len(syllables); n > 0 && syllables[n-1].kana {
+				syllables[n-1].long = true
+			}
+			continue
+		}
+
+		var text string
+		if i+1 < len(runes) {
+			if d, ok := hepburnDigraphs[string(runes[i:i+2])]; ok {
+				text = d
+				i++
+			}
+		}
+		if text == "" {
+			t, ok := hepburn[r]
+			if !ok {
+				syllables = append(syllables, syllable{text: string(r)})
+				geminate = false
+				continue
+			}
+			text = t
+		}
+
+		if opts.System == Kunrei {
+			if k, ok := kunreiOverrides[text]; ok {
+				text = k
+			}
+		}
+
+		if geminate {
+			switch {
+			case strings.HasPrefix(text, "ch"):
+				text = "t" + text
+			case text[0] != 'a' && text[0] != 'i' && text[0] != 'u' && text[0] != 'e' && text[0] != 'o' && text[0] != 'n':
+				text = text[:1] + text
+			}
+			geminate = false
+		}
+
+		syllables = append(syllables, syllable{text: text, kana: true})
+	}
+
+	// Join, applying the long vowel policy and the n' separator
+	var b strings.Builder
+	for i := 0; i < len(syllables); i++ {
+		s := syllables[i]
+		text := s.text
+
+		if s.kana && text == "n" && i+1 < len(syllables) && opts.LongVowels != Plain {
+			if next := syllables[i+1].text; next != "" && strings.ContainsRune("aiueoy", rune(next[0])) {
+				if opts.LongVowels == Wapuro {
+					text = "nn"
+				} else {
+					text = "n'"
+				}
+			}
+		}
+
+		long := s.long
+		if s.kana && !long && i+1 < len(syllables) && syllables[i+1].kana {
+			last := text[len(text)-1]
+			next := syllables[i+1].text
+			if (last == 'o' && (next == "u" || next == "o")) ||
+				(last == 'u' && next == "u") ||
+				(last == 'a' && next == "a") ||
+				(last == 'e' && next == "e") {
+				if opts.LongVowels != Wapuro {
+					long = true
+					i++ // The following vowel is absorbed into the long vowel
+				}
+			}
+		}
+
+		if long {
+			last := text[len(text)-1]
+			switch opts.LongVowels {
+			case Macrons:
+				text = text[:len(text)-1] + lengthen(last, opts.System)
+			case Wapuro:
+				text += string(last)
+			}
+		}
+
+		b.WriteString(text)
+	}
+
+	return b.String()
+}
+
+// lengthen returns the marked long form of a vowel
+func lengthen(vowel byte, system System) string {
+	macrons := map[byte]string{'a': "ā", 'i': "ī", 'u': "ū", 'e': "ē", 'o': "ō"}
+	circumflexes := map[byte]string{'a': "â", 'i': "î", 'u': "û", 'e': "ê", 'o': "ô"}
+	if system == Kunrei {
+		return circumflexes[vowel]
+	}
+	return macrons[vowel]
+}
+
+// Normalize reduces user-typed romaji to the form used for index keys:
+// lowercase ASCII letters only, with macrons and circumflexes folded to plain vowels.
+func Normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch r {
+		case 'ā', 'â':
+			r = 'a'
+		case 'ī', 'î':
+			r = 'i'
+		case 'ū', 'û':
+			r = 'u'
+		case 'ē', 'ê':
+			r = 'e'
+		case 'ō', 'ô':
+			r = 'o'
+		}
+		if r >= 'a' && r <= 'z' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// IsRomaji reports whether s looks like a romaji query (Latin letters with
+// optional long vowel marks, apostrophes, hyphens and spaces)
+func IsRomaji(s string) bool {
+	hasLetter := false
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			hasLetter = true
+		case strings.ContainsRune("āīūēōâîûêôĀĪŪĒŌ", r):
+			hasLetter = true
+		case r == '\'' || r == '-' || unicode.IsSpace(r):
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
+
+// IndexKeys returns the romaji keys under which a kana reading is indexed:
+// the wāpuro spelling (toukyou), which also keeps the う of verbs (omou), and the
+// plain Hepburn spelling with every long vowel collapsed (tokyo, gakko).
+// Readings containing non-kana characters produce no keys.
+func IndexKeys(kana string) []string {
+	for _, r := range ToHiragana(kana) {
+		if _, ok := hepburn[r]; !ok && r != 'っ' && r != 'ー' {
+			return nil
+		}
+	}
+
+	var keys []string
+	for _, lv := range []LongVowels{Wapuro, Plain} {
+		key := Normalize(ToRomaji(kana, Options{System: Hepburn, LongVowels: lv}))
+		if key == "" {
+			continue
+		}
+		if len(keys) == 0 || keys[len(keys)-1] != key {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// reverse maps romaji syllables back to hiragana for ToKana
+var reverse = buildReverse()
+
+// buildReverse inverts the Hepburn tables and adds Kunrei and wāpuro spellings.
+// When several kana share a spelling the plain (non-small, modern) kana wins, so
+// ぢ and づ and their digraphs are skipped: ja is always じゃ.
+func buildReverse() map[string]string {
+	m := make(map[string]string)
+	for d, text := range hepburnDigraphs {
+		if strings.ContainsRune("ぢづ", []rune(d)[0]) {
+			continue
+		}
+		m[text] = d
+	}
+	for r, text := range hepburn {
+		if strings.ContainsRune("ぁぃぅぇぉゃゅょゎゕゖゐゑをぢづ", r) {
+			continue
+		}
+		m[text] = string(r)
+	}
+	for hep, kun := range kunreiOverrides {
+		m[kun] = m[hep]
+	}
+	for text, kana := range map[string]string{
+		"wo": "を", "ti": "ち", "tu": "つ", "di": "ぢ", "du": "づ",
+		"jya": "じゃ", "jyu": "じゅ", "jyo": "じょ",
+		"cya": "ちゃ", "cyu": "ちゅ", "cyo": "ちょ",
+		"xa": "ぁ", "xi": "ぃ", "xu": "ぅ", "xe": "ぇ", "xo": "ぉ",
+		"xya": "ゃ", "xyu": "ゅ", "xyo": "ょ", "xtsu": "っ", "xtu": "っ",
+	} {
+		m[text] = kana
+	}
+	return m
+}
+
+// ToKana converts romaji (Hepburn, Kunrei or wāpuro input) to hiragana.
+// Unconvertible letters are passed through so callers can detect partial input.
+func ToKana(romaji string) string {
+	s := strings.ToLower(romaji)
+	for long, expanded := range map[string]string{
+		"ā": "aa", "ī": "ii", "ū": "uu", "ē": "ee", "ō": "ou",
+		"â": "aa", "î": "ii", "û": "uu", "ê": "ee", "ô": "ou",
+	} {
+		s = strings.ReplaceAll(s, long, expanded)
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		// ん: "nn", "n'" or n not followed by a vowel or y
+		if c == 'n' {
+			if i+1 < len(s) && (s[i+1] == 'n' || s[i+1] == '\'') {
+				b.WriteString("ん")
+				i += 2
+				continue
+			}
+			if i+1 == len(s) || !strings.ContainsRune("aiueoy", rune(s[i+1])) {
+				b.WriteString("ん")
+				i++
+				continue
+			}
+		}
+
+		// っ: doubled consonant (including "tch")
+		if i+1 < len(s) && c != 'n' && !strings.ContainsRune("aiueo", rune(c)) &&
+			(s[i+1] == c || (c == 't' && s[i+1] == 'c')) {
+			b.WriteString("っ")
+			i++
+			continue
+		}
+
+		if c == '-' {
+			b.WriteString("ー")
+			i++
+			continue
+		}
+
+		matched := false
+		for l := 4; l >= 1; l-- {
+			if i+l > len(s) {
+				continue
+			}
+			if kana, ok := reverse[s[i:i+l]]; ok {
+				b.WriteString(kana)
+				i += l
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
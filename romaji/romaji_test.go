@@ -0,0 +1,94 @@
+package romaji
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToRomaji(t *testing.T) {
+	testCases := []struct {
+		kana     string
+		opts     Options
+		expected string
+	}{
+		{"とうきょう", Options{Hepburn, Macrons}, "tōkyō"},
+		{"とうきょう", Options{Hepburn, Wapuro}, "toukyou"},
+		{"とうきょう", Options{Hepburn, Plain}, "tokyo"},
+		{"とうきょう", Options{Kunrei, Macrons}, "tôkyô"},
+		{"たべる", Options{Hepburn, Macrons}, "taberu"},
+		{"しんぶん", Options{Hepburn, Macrons}, "shinbun"},
+		{"ちゃ", Options{Kunrei, Plain}, "tya"},
+		{"きって", Options{Hepburn, Macrons}, "kitte"},
+		{"まっちゃ", Options{Hepburn, Macrons}, "matcha"},
+		{"げんいん", Options{Hepburn, Macrons}, "gen'in"},
+		{"コーヒー", Options{Hepburn, Macrons}, "kōhī"},
+		{"コーヒー", Options{Hepburn, Wapuro}, "koohii"},
+	}
+
+	for _, tc := range testCases {
+		if got := ToRomaji(tc.kana, tc.opts); got != tc.expected {
+			t.Errorf("ToRomaji(%s, %+v) = %s, want %s", tc.kana, tc.opts, got, tc.expected)
+		}
+	}
+}
+
+func TestToKana(t *testing.T) {
+	testCases := []struct {
+		romaji   string
+		expected string
+	}{
+		{"taberu", "たべる"},
+		{"toukyou", "とうきょう"},
+		{"tōkyō", "とうきょう"},
+		{"shinbun", "しんぶん"},
+		{"sinbun", "しんぶん"},
+		{"kitte", "きって"},
+		{"matcha", "まっちゃ"},
+		{"gen'in", "げんいん"},
+		{"Tsukue", "つくえ"},
+		{"ja", "じゃ"}, // Not ぢゃ, which is also read ja
+		{"zyo", "じょ"},
+	}
+
+	for _, tc := range testCases {
+		if got := ToKana(tc.romaji); got != tc.expected {
+			t.Errorf("ToKana(%s) = %s, want %s", tc.romaji, got, tc.expected)
+		}
+	}
+}
+
+func TestIndexKeys(t *testing.T) {
+	testCases := []struct {
+		kana     string
+		expected []string
+	}{
+		{"とうきょう", []string{"toukyou", "tokyo"}},
+		{"たべる", []string{"taberu"}},
+		{"おもう", []string{"omou", "omo"}}, // The wāpuro key keeps the う of verbs
+		{"すう", []string{"suu", "su"}},
+		{"がっこう", []string{"gakkou", "gakko"}},
+		{"こうこう", []string{"koukou", "koko"}},
+		{"きゅう", []string{"kyuu", "kyu"}},
+		{"げんいん", []string{"gennin", "genin"}},
+		{"ＡＢＣ", nil},
+	}
+
+	for _, tc := range testCases {
+		if got := IndexKeys(tc.kana); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("IndexKeys(%s) = %v, want %v", tc.kana, got, tc.expected)
+		}
+	}
+}
+
+func TestIsRomaji(t *testing.T) {
+	for _, s := range []string{"taberu", "Tōkyō", "gen'in", "ko-hi"} {
+		if !IsRomaji(s) {
+			t.Errorf("Expected %s to be romaji", s)
+		}
+	}
+	for _, s := range []string{"", "食べる", "たべる", "123", "tabe1"} {
+		if IsRomaji(s) {
+			t.Errorf("Expected %s not to be romaji", s)
+		}
+	}
+}
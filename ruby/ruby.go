@@ -0,0 +1,416 @@
+package ruby
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"kiokun-go/conjugation"
+	"kiokun-go/dictionaries/jmdict"
+	"kiokun-go/dictionaries/kanjidic"
+	"kiokun-go/furigana"
+	"kiokun-go/lookup"
+	"kiokun-go/romaji"
+)
+
+// Span is a run of text with the reading shown above it; Ruby is empty for
+// text that needs no annotation
+type Span struct {
+	Text string `json:"t"`
+	Ruby string `json:"r,omitempty"`
+}
+
+// Format selects how annotated text is rendered
+type Format string
+
+const (
+	HTML     Format = "html"     // <ruby>食<rt>た</rt></ruby>べる
+	Brackets Format = "brackets" // Anki style: 日本語[にほんご]を 勉強[べんきょう]
+	JSON     Format = "json"     // [{"t":"食","r":"た"},{"t":"べる"}]
+)
+
+// Annotator adds furigana to Japanese text using a built dictionary
+type Annotator struct {
+	dict     *lookup.Dictionary
+	readings furigana.Readings
+	kanji    map[rune]*kanjidic.Kanji
+}
+
+// NewAnnotator returns an Annotator reading from a dictionary
+func NewAnnotator(dict *lookup.Dictionary) *Annotator {
+	return &Annotator{
+		dict:     dict,
+		readings: make(furigana.Readings),
+		kanji:    make(map[rune]*kanjidic.Kanji),
+	}
+}
+
+// Annotate segments text with lookup.Dictionary.Scan and gives every kanji a reading.
+// Words matched to JMdict take the reading of the matched form, preferring common
+// kanji forms and common readings; inflected words keep the reading of their stem,
+// which comes from the conjugation tables when it changes (来る, 来ない).
+// Kanji outside any JMdict match fall back to their first Kanjidic reading.
+func (a *Annotator) Annotate(text string) ([]Span, error) {
+	tokens, err := a.dict.Scan(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var spans []Span
+	for _, token := range tokens {
+		if !hasKanji(token.Text) {
+			spans = appendSpan(spans, Span{Text: token.Text})
+			continue
+		}
+
+		parts, err := a.wordParts(token)
+		if err != nil {
+			return nil, err
+		}
+		if parts == nil {
+			if parts, err = a.kanjiParts(token.Text); err != nil {
+				return nil, err
+			}
+		}
+		for _, part := range parts {
+			spans = appendSpan(spans, Span{Text: part.Base, Ruby: part.Ruby})
+		}
+	}
+	return spans, nil
+}
+
+// wordParts returns the furigana of a token matched to a JMdict word, or nil
+func (a *Annotator) wordParts(token lookup.Token) ([]jmdict.FuriganaPart, error) {
+	if token.Match == nil {
+		return nil, nil
+	}
+
+	var best *jmdict.Word
+	var bestForm, bestReading string
+	bestScore := -1
+
+	for _, id := range token.Match.E["j"] {
+		entry, err := a.dict.Entry("j", id)
+		if err != nil {
+			return nil, err
+		}
+		word := entry.(jmdict.Word)
+
+		forms := word.Forms
+		if forms == nil {
+			forms = word.FormMatrix()
+		}
+		formIndex, ok := token.Match.MatchedForm("j", id)
+		if !ok || formIndex >= len(forms) {
+			continue
+		}
+		form := forms[formIndex]
+
+		for _, reading := range form.Readings {
+			score := 0
+			if isCommonKanji(word, form.Text) {
+				score += 2
+			}
+			if isCommonKana(word, reading.Text) {
+				score++
+			}
+			if score > bestScore {
+				best, bestForm, bestReading, bestScore = &word, form.Text, reading.Text, score
+			}
+		}
+	}
+	if best == nil || !hasKanji(bestForm) {
+		return nil, nil
+	}
+
+	parts, err := a.align(*best, bestForm, bestReading)
+	if err != nil {
+		return nil, err
+	}
+	if token.Text != bestForm {
+		if stem, ruby, ok := stemReading(*best, bestForm, bestReading, token.Text); ok {
+			parts = reread(parts, stem, ruby)
+		}
+	}
+	return applyToSurface(parts, token.Text), nil
+}
+
+// stemReading returns the kanji stem of an inflected word and its reading in the
+// inflection the surface text uses, from the conjugation tables of the form and its
+// reading: 来ない is 来[こ]ない and 来ます 来[き]ます although 来る is 来[く]る.
+// The inflection is picked by the first kana after the stem.
+func stemReading(word jmdict.Word, form, reading, surface string) (string, string, bool) {
+	for _, sense := range word.Sense {
+		for _, pos := range sense.PartOfSpeech {
+			formTable, ok := conjugation.Conjugate(form, jmdict.PartOfSpeech(pos))
+			if !ok {
+				continue
+			}
+			readingTable, ok := conjugation.Conjugate(reading, jmdict.PartOfSpeech(pos))
+			if !ok {
+				continue
+			}
+			forms, readings := inflections(formTable), inflections(readingTable)
+			for i, f := range forms {
+				stem, tail := splitStem(f)
+				if tail == "" || !strings.HasSuffix(readings[i], tail) {
+					continue
+				}
+				_, size := utf8.DecodeRuneInString(tail)
+				if strings.HasPrefix(surface, stem+tail[:size]) {
+					return stem, strings.TrimSuffix(readings[i], tail), true
+				}
+			}
+		}
+	}
+	return "", "", false
+}
+
+// inflections lists the forms of a conjugation table in a fixed order, the
+// dictionary form first
+func inflections(c jmdict.Conjugation) []string {
+	return []string{c.Form, c.Polite, c.Negative, c.Past, c.Te, c.Potential, c.Passive,
+		c.Causative, c.Volitional, c.Imperative, c.Conditional}
+}
+
+// splitStem splits an inflected form after its last kanji
+func splitStem(form string) (string, string) {
+	end := 0
+	for i, r := range form {
+		if isKanji(r) {
+			end = i + utf8.RuneLen(r)
+		}
+	}
+	return form[:end], form[end:]
+}
+
+// reread gives the parts covering a stem the stem's reading. Parts before the last
+// kanji keep their readings and the last kanji takes the rest; when the readings do
+// not fit, the whole stem is annotated as one group.
+func reread(parts []jmdict.FuriganaPart, stem, ruby string) []jmdict.FuriganaPart {
+	var result []jmdict.FuriganaPart
+	rest := stem
+	for _, part := range parts {
+		if rest == "" {
+			break
+		}
+		if !strings.HasPrefix(rest, part.Base) {
+			return []jmdict.FuriganaPart{{Base: stem, Ruby: ruby}}
+		}
+		result = append(result, part)
+		rest = rest[len(part.Base):]
+	}
+	if rest != "" || len(result) == 0 {
+		return []jmdict.FuriganaPart{{Base: stem, Ruby: ruby}}
+	}
+
+	last := len(result) - 1
+	var before strings.Builder
+	for _, part := range result[:last] {
+		if part.Ruby != "" {
+			before.WriteString(part.Ruby)
+		} else {
+			before.WriteString(part.Base)
+		}
+	}
+	tail, ok := strings.CutPrefix(ruby, before.String())
+	if !ok || tail == "" {
+		return []jmdict.FuriganaPart{{Base: stem, Ruby: ruby}}
+	}
+	result[last].Ruby = tail
+	return result
+}
+
+// align returns the furigana of a form and reading, using the word's precomputed
+// alignment when present
+func (a *Annotator) align(word jmdict.Word, form, reading string) ([]jmdict.FuriganaPart, error) {
+	for _, f := range word.Furigana {
+		if f.Text == form && f.Reading == reading {
+			return f.Parts, nil
+		}
+	}
+
+	for _, r := range form {
+		if _, err := a.kanjidic(r); err != nil {
+			return nil, err
+		}
+	}
+	return furigana.Align(form, reading, a.readings), nil
+}
+
+// applyToSurface carries a dictionary form's furigana over to the text as written.
+// Parts are used while the surface text still starts with them (食べなかった keeps
+// 食[た]); the inflected remainder is left unannotated.
+func applyToSurface(parts []jmdict.FuriganaPart, surface string) []jmdict.FuriganaPart {
+	var result []jmdict.FuriganaPart
+	rest := surface
+	for _, part := range parts {
+		if !strings.HasPrefix(rest, part.Base) {
+			break
+		}
+		result = append(result, part)
+		rest = rest[len(part.Base):]
+	}
+	if rest != "" {
+		result = append(result, jmdict.FuriganaPart{Base: rest})
+	}
+	return result
+}
+
+// kanjiParts annotates each kanji of unmatched text with its first Kanjidic reading
+func (a *Annotator) kanjiParts(text string) ([]jmdict.FuriganaPart, error) {
+	var parts []jmdict.FuriganaPart
+	for _, r := range text {
+		part := jmdict.FuriganaPart{Base: string(r)}
+		if isKanji(r) {
+			k, err := a.kanjidic(r)
+			if err != nil {
+				return nil, err
+			}
+			if k != nil {
+				part.Ruby = kanjiReading(*k)
+			}
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// kanjiReading picks one reading for a kanji on its own: the first on'yomi in
+// hiragana, or else the stem of the first kun'yomi
+func kanjiReading(k kanjidic.Kanji) string {
+	if len(k.OnYomi) > 0 {
+		return romaji.ToHiragana(strings.Trim(k.OnYomi[0], "-"))
+	}
+	if len(k.KunYomi) > 0 {
+		stem, _, _ := strings.Cut(strings.Trim(k.KunYomi[0], "-"), ".")
+		return stem
+	}
+	return ""
+}
+
+// kanjidic loads a kanji's Kanjidic entry and registers its readings for alignment.
+// It returns nil when the kanji is not in Kanjidic.
+func (a *Annotator) kanjidic(r rune) (*kanjidic.Kanji, error) {
+	if k, ok := a.kanji[r]; ok {
+		return k, nil
+	}
+
+	var result *kanjidic.Kanji
+	entry, err := a.dict.Index(string(r))
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		for _, id := range entry.E["d"] {
+			e, err := a.dict.Entry("d", id)
+			if err != nil {
+				return nil, err
+			}
+			k := e.(kanjidic.Kanji)
+			a.readings.AddKanjidic(k)
+			result = &k
+			break
+		}
+	}
+
+	a.kanji[r] = result
+	return result, nil
+}
+
+// isCommonKanji reports whether a kanji form of a word is marked common
+func isCommonKanji(w jmdict.Word, text string) bool {
+	for _, k := range w.Kanji {
+		if k.Text == text {
+			return k.Common
+		}
+	}
+	return false
+}
+
+// isCommonKana reports whether a kana reading of a word is marked common
+func isCommonKana(w jmdict.Word, text string) bool {
+	for _, k := range w.Kana {
+		if k.Text == text {
+			return k.Common
+		}
+	}
+	return false
+}
+
+// appendSpan adds a span, merging consecutive unannotated text
+func appendSpan(spans []Span, span Span) []Span {
+	if span.Text == "" {
+		return spans
+	}
+	if n := len(spans); n > 0 && span.Ruby == "" && spans[n-1].Ruby == "" {
+		spans[n-1].Text += span.Text
+		return spans
+	}
+	return append(spans, span)
+}
+
+// isKanji reports whether a character takes a reading
+func isKanji(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+// hasKanji reports whether text contains any kanji
+func hasKanji(text string) bool {
+	for _, r := range text {
+		if isKanji(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToHTML renders spans with <ruby> and <rt> elements
+func ToHTML(spans []Span) string {
+	var b strings.Builder
+	for _, s := range spans {
+		if s.Ruby == "" {
+			b.WriteString(html.EscapeString(s.Text))
+			continue
+		}
+		fmt.Fprintf(&b, "<ruby>%s<rt>%s</rt></ruby>", html.EscapeString(s.Text), html.EscapeString(s.Ruby))
+	}
+	return b.String()
+}
+
+// ToBrackets renders spans in Anki bracket notation, where a space marks
+// where each annotated base starts: 日本語[にほんご]を 勉強[べんきょう]
+func ToBrackets(spans []Span) string {
+	var b strings.Builder
+	for _, s := range spans {
+		if s.Ruby != "" && b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(s.Text)
+		if s.Ruby != "" {
+			fmt.Fprintf(&b, "[%s]", s.Ruby)
+		}
+	}
+	return b.String()
+}
+
+// Render renders spans in the given format
+func Render(spans []Span, format Format) (string, error) {
+	switch format {
+	case HTML:
+		return ToHTML(spans), nil
+	case Brackets:
+		return ToBrackets(spans), nil
+	case JSON:
+		data, err := json.Marshal(spans)
+		if err != nil {
+			return "", fmt.Errorf("error encoding spans: %v", err)
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unknown ruby format: %s", format)
+	}
+}
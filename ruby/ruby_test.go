@@ -0,0 +1,105 @@
+package ruby
+
+import (
+	"path/filepath"
+	"testing"
+
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/jmdict"
+	"kiokun-go/dictionaries/kanjidic"
+	"kiokun-go/lookup"
+	"kiokun-go/processor"
+)
+
+// buildTestAnnotator writes a small sharded dictionary into a temp directory
+func buildTestAnnotator(t *testing.T) *Annotator {
+	t.Helper()
+
+	entries := []common.Entry{
+		jmdict.Word{
+			ID:    "1464560",
+			Kanji: []jmdict.KanjiEntry{{Text: "日本語", Common: true}},
+			Kana:  []jmdict.KanaEntry{{Text: "にほんご", Common: true}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"n"}}},
+		},
+		jmdict.Word{
+			ID:    "1403570",
+			Kanji: []jmdict.KanjiEntry{{Text: "勉強", Common: true}},
+			Kana:  []jmdict.KanaEntry{{Text: "べんきょう", Common: true}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"n", "vs"}}},
+		},
+		jmdict.Word{
+			ID:    "1358280",
+			Kanji: []jmdict.KanjiEntry{{Text: "食べる", Common: true}},
+			Kana:  []jmdict.KanaEntry{{Text: "たべる", Common: true}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"v1"}}},
+		},
+		jmdict.Word{
+			ID:    "1547720",
+			Kanji: []jmdict.KanjiEntry{{Text: "来る", Common: true}},
+			Kana:  []jmdict.KanaEntry{{Text: "くる", Common: true}},
+			Sense: []jmdict.Sense{{PartOfSpeech: []string{"vk"}}},
+		},
+		kanjidic.Kanji{Character: "日", NumericID: "1", OnYomi: []string{"ニチ", "ジツ"}, KunYomi: []string{"ひ"}, Nanori: []string{"に"}},
+		kanjidic.Kanji{Character: "本", NumericID: "2", OnYomi: []string{"ホン"}, KunYomi: []string{"もと"}},
+		kanjidic.Kanji{Character: "語", NumericID: "3", OnYomi: []string{"ゴ"}, KunYomi: []string{"かた.る"}},
+		kanjidic.Kanji{Character: "勉", NumericID: "4", OnYomi: []string{"ベン"}},
+		kanjidic.Kanji{Character: "強", NumericID: "5", OnYomi: []string{"キョウ", "ゴウ"}, KunYomi: []string{"つよ.い"}},
+		kanjidic.Kanji{Character: "食", NumericID: "6", OnYomi: []string{"ショク"}, KunYomi: []string{"た.べる", "く.う"}},
+		kanjidic.Kanji{Character: "来", NumericID: "8", OnYomi: []string{"ライ"}, KunYomi: []string{"く.る", "きた.る", "こ"}},
+		kanjidic.Kanji{Character: "猫", NumericID: "7", OnYomi: []string{"ビョウ"}, KunYomi: []string{"ねこ"}},
+	}
+
+	baseDir := filepath.Join(t.TempDir(), "dict")
+	p, err := processor.NewShardedIndexProcessor(baseDir, 1)
+	if err != nil {
+		t.Fatalf("Error creating processor: %v", err)
+	}
+	if err := p.ProcessEntries(entries); err != nil {
+		t.Fatalf("Error processing entries: %v", err)
+	}
+	if err := p.WriteToFiles(); err != nil {
+		t.Fatalf("Error writing files: %v", err)
+	}
+
+	d, err := lookup.Open(baseDir)
+	if err != nil {
+		t.Fatalf("Error opening dictionary: %v", err)
+	}
+	return NewAnnotator(d)
+}
+
+func TestAnnotate(t *testing.T) {
+	a := buildTestAnnotator(t)
+
+	testCases := []struct {
+		text     string
+		format   Format
+		expected string
+	}{
+		{"日本語を勉強した。", Brackets, "日[に] 本[ほん] 語[ご]を 勉[べん] 強[きょう]した。"},
+		{"食べなかった", HTML, "<ruby>食<rt>た</rt></ruby>べなかった"},
+		{"来る", Brackets, "来[く]る"},
+		{"来ない", Brackets, "来[こ]ない"}, // The stem reading changes with the inflection
+		{"来ます", Brackets, "来[き]ます"},
+		{"来なかった", Brackets, "来[こ]なかった"},
+		{"猫", JSON, `[{"t":"猫","r":"びょう"}]`},
+		{"ひらがな & ABC", HTML, "ひらがな &amp; ABC"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			spans, err := a.Annotate(tc.text)
+			if err != nil {
+				t.Fatalf("Annotate error: %v", err)
+			}
+			got, err := Render(spans, tc.format)
+			if err != nil {
+				t.Fatalf("Render error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("Annotate(%s) = %s, want %s", tc.text, got, tc.expected)
+			}
+		})
+	}
+}
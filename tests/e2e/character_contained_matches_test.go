@@ -327,3 +327,45 @@ func cleanupTestFiles(outputDir string) error {
 	
 	return nil
 }
+
+// TestConsolidatedContainedMatches tests that with consolidated keys a character's
+// contained matches are all in one index file, in the character's own shard
+func TestConsolidatedContainedMatches(t *testing.T) {
+	testChar := "日"
+	entries := createTestData(testChar, t)
+	outputDir := filepath.Join(t.TempDir(), "output")
+
+	proc, err := processor.NewShardedIndexProcessor(outputDir, 1)
+	if err != nil {
+		t.Fatalf("Error creating processor: %v", err)
+	}
+	proc.SetConsolidateKeys(true)
+	if err := proc.ProcessEntries(entries); err != nil {
+		t.Fatalf("Error processing entries: %v", err)
+	}
+	if err := proc.WriteToFiles(); err != nil {
+		t.Fatalf("Error writing files: %v", err)
+	}
+
+	var found []string
+	var contained int
+	for _, suffix := range []string{"_han_1char", "_han_2char", "_han_3plus", "_non_han"} {
+		indexPath := filepath.Join(outputDir+suffix, "index", testChar+".json.br")
+		if _, err := os.Stat(indexPath); os.IsNotExist(err) {
+			continue
+		}
+		indexEntry, err := readIndexFile(indexPath)
+		if err != nil {
+			t.Fatalf("Error reading %s: %v", indexPath, err)
+		}
+		found = append(found, suffix)
+		contained += len(indexEntry.C["j"])
+	}
+
+	if len(found) != 1 || found[0] != "_han_1char" {
+		t.Fatalf("Expected the index file for %s only in _han_1char, found it in %v", testChar, found)
+	}
+	if expected := len(generateWordsContaining(testChar)); contained != expected {
+		t.Errorf("Expected %d contained matches in one file, got %d", expected, contained)
+	}
+}
@@ -0,0 +1,215 @@
+package variants
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/chinese_words"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/kanjidic"
+)
+
+// maxDistance bounds how many variant links are followed from a character, so
+// 鉄 reaches 铁 through 鐵 without chaining into unrelated characters
+const maxDistance = 2
+
+// Graph links characters that are variants of each other: Japanese shinjitai and
+// kyūjitai (国/國), simplified and traditional Chinese (国/國, 铁/鐵) and the
+// variants listed in Kanjidic
+type Graph struct {
+	edges map[string]map[string]bool
+
+	mu        sync.Mutex
+	canonical map[string]string // Canonical of every linked character, built on first use
+}
+
+// NewGraph returns an empty graph
+func NewGraph() *Graph {
+	return &Graph{edges: make(map[string]map[string]bool)}
+}
+
+// FromEntries builds a graph from the shinjitai/kyūjitai table, Kanjidic variants,
+// Chinese character simplified/traditional forms and variants, and single-character
+// Chinese word pairs. Other entries are ignored.
+func FromEntries(entries []common.Entry) *Graph {
+	g := NewGraph()
+	g.AddShinjitai()
+
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case kanjidic.Kanji:
+			for _, v := range e.Variants {
+				g.Add(e.Character, v)
+			}
+		case chinese_chars.ChineseCharEntry:
+			g.Add(e.Traditional, e.Simplified)
+			for _, v := range e.SimpVariants {
+				g.Add(e.Traditional, v)
+			}
+			for _, v := range e.TradVariants {
+				g.Add(e.Traditional, v)
+			}
+		case chinese_words.ChineseWordEntry:
+			if len([]rune(e.Traditional)) == 1 {
+				g.Add(e.Traditional, e.Simplified)
+			}
+		}
+	}
+	return g
+}
+
+// Add links two single characters. Identical characters, empty strings and
+// longer strings are ignored.
+func (g *Graph) Add(a, b string) {
+	if a == b || len([]rune(a)) != 1 || len([]rune(b)) != 1 {
+		return
+	}
+	g.mu.Lock()
+	g.canonical = nil
+	g.mu.Unlock()
+	for _, pair := range [][2]string{{a, b}, {b, a}} {
+		if g.edges[pair[0]] == nil {
+			g.edges[pair[0]] = make(map[string]bool)
+		}
+		g.edges[pair[0]][pair[1]] = true
+	}
+}
+
+// AddShinjitai links every shinjitai in the built-in table to its kyūjitai
+func (g *Graph) AddShinjitai() {
+	for _, pair := range strings.Fields(shinjitai) {
+		runes := []rune(pair)
+		for _, old := range runes[1:] {
+			g.Add(string(runes[0]), string(old))
+		}
+	}
+}
+
+// Variants returns the characters within two links of a character, sorted, not
+// including the character itself
+func (g *Graph) Variants(char string) []string {
+	seen := map[string]bool{char: true}
+	frontier := []string{char}
+	var result []string
+
+	for depth := 0; depth < maxDistance && len(frontier) > 0; depth++ {
+		var next []string
+		for _, c := range frontier {
+			for v := range g.edges[c] {
+				if seen[v] {
+					continue
+				}
+				seen[v] = true
+				result = append(result, v)
+				next = append(next, v)
+			}
+		}
+		frontier = next
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// Canonical returns the representative of a character and its variants: the
+// smallest character by code point among all characters connected to it, however
+// many links away, so 国 and 國 share one representative and every character of a
+// chain a–b–c–d has the same one
+func (g *Graph) Canonical(char string) string {
+	if canonical, ok := g.canonicals()[char]; ok {
+		return canonical
+	}
+	return char
+}
+
+// canonicals returns the canonical of every linked character, computing them after
+// the graph changes
+func (g *Graph) canonicals() map[string]string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.canonical == nil {
+		g.canonical = g.components()
+	}
+	return g.canonical
+}
+
+// components maps every linked character to the smallest character of its
+// connected component, using union-find
+func (g *Graph) components() map[string]string {
+	parent := make(map[string]string, len(g.edges))
+	var find func(c string) string
+	find = func(c string) string {
+		p, ok := parent[c]
+		if !ok || p == c {
+			return c
+		}
+		root := find(p)
+		parent[c] = root
+		return root
+	}
+
+	for a, linked := range g.edges {
+		for b := range linked {
+			ra, rb := find(a), find(b)
+			if ra == rb {
+				continue
+			}
+			// The smaller root wins, so each root is the smallest of its component
+			if rb < ra {
+				ra, rb = rb, ra
+			}
+			parent[rb] = ra
+		}
+	}
+
+	canonical := make(map[string]string, len(g.edges))
+	for c := range g.edges {
+		canonical[c] = find(c)
+	}
+	return canonical
+}
+
+// Normalize replaces every character of a text by its canonical variant, so
+// spellings that differ only by variant characters normalize to the same text
+func (g *Graph) Normalize(text string) string {
+	canonicals := g.canonicals()
+	var b strings.Builder
+	for _, r := range text {
+		if canonical, ok := canonicals[string(r)]; ok {
+			b.WriteString(canonical)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Characters returns every character with at least one variant, sorted
+func (g *Graph) Characters() []string {
+	chars := make([]string, 0, len(g.edges))
+	for c := range g.edges {
+		chars = append(chars, c)
+	}
+	sort.Strings(chars)
+	return chars
+}
+
+// shinjitai lists shinjitai followed by their kyūjitai, one group per field
+const shinjitai = `
+亜亞 悪惡 圧壓 囲圍 医醫 為爲 壱壹 隠隱 栄榮 営營 衛衞 駅驛 円圓 塩鹽 縁緣 応應 欧歐 殴毆 桜櫻 奥奧
+仮假 価價 画畫 会會 絵繪 壊壞 懐懷 拡擴 覚覺 学學 岳嶽 楽樂 巻卷 陥陷 寛寬 関關 歓歡 観觀 気氣 帰歸
+既旣 亀龜 偽僞 戯戲 犠犧 旧舊 拠據 挙擧 峡峽 狭狹 暁曉 区區 駆驅 勲勳 薫薰 径徑 恵惠 掲揭 渓溪 経經
+蛍螢 軽輕 継繼 鶏鷄 芸藝 撃擊 欠缺 倹儉 剣劍 圏圈 険險 検檢 献獻 権權 県縣 顕顯 験驗 厳嚴 広廣 効效
+恒恆 黄黃 鉱鑛 号號 国國 黒黑 済濟 砕碎 斎齋 剤劑 雑雜 参參 蚕蠶 惨慘 賛贊 残殘 糸絲 歯齒 児兒 辞辭
+湿濕 実實 写寫 釈釋 寿壽 収收 従從 渋澁 獣獸 縦縱 粛肅 処處 叙敍 奨獎 将將 焼燒 称稱 証證 乗乘 剰剩
+壌壤 嬢孃 条條 浄淨 状狀 畳疊 譲讓 醸釀 触觸 嘱囑 寝寢 慎愼 真眞 尽盡 図圖 粋粹 酔醉 随隨 髄髓 数數
+枢樞 瀬瀨 声聲 静靜 斉齊 摂攝 窃竊 専專 戦戰 浅淺 潜潛 践踐 銭錢 禅禪 双雙 壮壯 争爭 荘莊 捜搜 挿插
+巣巢 装裝 総總 騒騷 増增 蔵藏 臓臟 属屬 続續 堕墮 対對 体體 帯帶 滞滯 台臺 滝瀧 択擇 沢澤 担擔 単單
+胆膽 団團 断斷 弾彈 遅遲 痴癡 虫蟲 昼晝 鋳鑄 庁廳 聴聽 鎮鎭 逓遞 鉄鐵 転轉 点點 伝傳 灯燈 当當 党黨
+盗盜 稲稻 闘鬭 徳德 独獨 読讀 届屆 弐貳 悩惱 脳腦 廃廢 拝拜 売賣 麦麥 発發 髪髮 抜拔 晩晚 蛮蠻 秘祕
+浜濱 払拂 仏佛 変變 辺邊 弁辯辨瓣 舗舖 宝寶 豊豐 没沒 翻飜 万萬 満滿 黙默 訳譯 薬藥 与與 予豫 余餘
+誉譽 揺搖 様樣 謡謠 来來 乱亂 覧覽 竜龍 両兩 猟獵 塁壘 励勵 礼禮 隷隸 霊靈 齢齡 恋戀 炉爐 労勞 楼樓
+湾灣
+`
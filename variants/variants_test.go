@@ -0,0 +1,76 @@
+package variants
+
+import (
+	"reflect"
+	"testing"
+
+	"kiokun-go/dictionaries/chinese_chars"
+	"kiokun-go/dictionaries/common"
+	"kiokun-go/dictionaries/kanjidic"
+)
+
+func TestFromEntries(t *testing.T) {
+	g := FromEntries([]common.Entry{
+		chinese_chars.ChineseCharEntry{ID: "1", Traditional: "鐵", Simplified: "铁", SimpVariants: []string{"铁"}},
+		chinese_chars.ChineseCharEntry{ID: "2", Traditional: "著", Simplified: "着", SimpVariants: []string{"着", "著"}},
+		kanjidic.Kanji{Character: "峰", Variants: []string{"峯"}},
+	})
+
+	testCases := []struct {
+		char     string
+		expected []string
+	}{
+		{"国", []string{"國"}},
+		{"鉄", []string{"鐵", "铁"}},
+		{"铁", []string{"鉄", "鐵"}},
+		{"峯", []string{"峰"}},
+		{"着", []string{"著"}},
+		{"弁", []string{"瓣", "辨", "辯"}},
+		{"日", nil},
+	}
+
+	for _, tc := range testCases {
+		if got := g.Variants(tc.char); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Variants(%s) = %v, want %v", tc.char, got, tc.expected)
+		}
+	}
+}
+
+func TestAddIgnoresNonCharacters(t *testing.T) {
+	g := NewGraph()
+	g.Add("国", "国")
+	g.Add("中国", "中國")
+	g.Add("", "國")
+	if chars := g.Characters(); len(chars) != 0 {
+		t.Errorf("Expected an empty graph, got %v", chars)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	g := FromEntries([]common.Entry{
+		chinese_chars.ChineseCharEntry{ID: "1", Traditional: "鐵", Simplified: "铁"},
+	})
+
+	for _, text := range []string{"鉄道", "鐵道", "铁道"} {
+		if got := g.Normalize(text); got != "鉄道" {
+			t.Errorf("Normalize(%s) = %s, want 鉄道", text, got)
+		}
+	}
+}
+
+func TestCanonicalFollowsChains(t *testing.T) {
+	// 丈 and 丁 are three links apart, beyond the reach of Variants
+	g := NewGraph()
+	g.Add("丈", "万")
+	g.Add("万", "七")
+	g.Add("七", "丁")
+
+	for _, char := range []string{"丈", "万", "七", "丁"} {
+		if got := g.Canonical(char); got != "丁" {
+			t.Errorf("Canonical(%s) = %s, want 丁", char, got)
+		}
+	}
+	if g.Normalize("丈夫") != g.Normalize("丁夫") {
+		t.Errorf("Expected 丈夫 and 丁夫 to normalize alike")
+	}
+}